@@ -0,0 +1,88 @@
+package main
+
+import "fmt"
+
+type LoxClass struct {
+	name       string
+	superclass *LoxClass
+	methods    map[string]*LoxFunction
+}
+
+// findMethod looks up a method on the class, walking up the superclass
+// chain if the class does not define it itself.
+func (c *LoxClass) findMethod(name string) (*LoxFunction, bool) {
+	if method, ok := c.methods[name]; ok {
+		return method, true
+	}
+
+	if c.superclass != nil {
+		return c.superclass.findMethod(name)
+	}
+
+	return nil, false
+}
+
+// Arity is the arity of the class's initializer, or zero if it has none.
+func (c *LoxClass) Arity() int {
+	if initializer, ok := c.findMethod("init"); ok {
+		return initializer.Arity()
+	}
+	return 0
+}
+
+// parameters are the parameters of the class's initializer, so classes
+// accept named arguments and defaults like functions do.
+func (c *LoxClass) parameters() []Param {
+	if initializer, ok := c.findMethod("init"); ok {
+		return initializer.params
+	}
+	return []Param{}
+}
+
+// Call creates a new instance of the class and runs its initializer,
+// if any, bound to that instance.
+func (c *LoxClass) Call(args []any) (any, error) {
+	instance := &LoxInstance{
+		class:  c,
+		fields: make(map[string]any),
+	}
+
+	if initializer, ok := c.findMethod("init"); ok {
+		if _, err := initializer.bind(instance).Call(args); err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
+}
+
+func (c *LoxClass) String() string {
+	return c.name
+}
+
+type LoxInstance struct {
+	class  *LoxClass
+	fields map[string]any
+}
+
+// Get looks up a property on the instance.  Fields shadow methods, and
+// methods are bound to the instance so they remember 'this'.
+func (i *LoxInstance) Get(name Token) (any, error) {
+	if v, ok := i.fields[name.Lexeme]; ok {
+		return v, nil
+	}
+
+	if method, ok := i.class.findMethod(name.Lexeme); ok {
+		return method.bind(i), nil
+	}
+
+	return nil, fmt.Errorf("undefined property '%s'", name.Lexeme)
+}
+
+func (i *LoxInstance) Set(name Token, v any) {
+	i.fields[name.Lexeme] = v
+}
+
+func (i *LoxInstance) String() string {
+	return i.class.name + " instance"
+}
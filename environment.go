@@ -28,7 +28,10 @@ func (e *Environment) Get(name Token) (any, error) {
 		return e.enclosing.Get(name)
 	}
 
-	return nil, fmt.Errorf("undefined variable '%s'", name.Lexeme)
+	return nil, &RuntimeError{
+		Line:    name.Line,
+		Message: fmt.Sprintf("undefined variable '%s'", name.Lexeme),
+	}
 }
 
 func (e *Environment) Assign(name Token, v any) error {
@@ -41,5 +44,67 @@ func (e *Environment) Assign(name Token, v any) error {
 		return e.enclosing.Assign(name, v)
 	}
 
-	return fmt.Errorf("undefined variable '%s'", name.Lexeme)
+	return &RuntimeError{
+		Line:    name.Line,
+		Message: fmt.Sprintf("undefined variable '%s'", name.Lexeme),
+	}
+}
+
+// GetAt looks name up in the environment distance hops up the chain,
+// where the resolver found it declared.
+func (e *Environment) GetAt(distance int, name Token) (any, error) {
+	v, ok := e.ancestor(distance).values[name.Lexeme]
+	if !ok {
+		return nil, &RuntimeError{
+			Line:    name.Line,
+			Message: fmt.Sprintf("undefined variable '%s'", name.Lexeme),
+		}
+	}
+	return v, nil
+}
+
+// AssignAt assigns name in the environment distance hops up the chain.
+func (e *Environment) AssignAt(distance int, name Token, v any) error {
+	env := e.ancestor(distance)
+	if _, ok := env.values[name.Lexeme]; !ok {
+		return &RuntimeError{
+			Line:    name.Line,
+			Message: fmt.Sprintf("undefined variable '%s'", name.Lexeme),
+		}
+	}
+	env.values[name.Lexeme] = v
+	return nil
+}
+
+func (e *Environment) ancestor(distance int) *Environment {
+	env := e
+	for range distance {
+		env = env.enclosing
+	}
+	return env
+}
+
+// binding records where the resolver found the declaration a variable
+// reference refers to.  The zero value is a global, looked up by name in
+// the global environment, as is every reference the resolver has not
+// seen.
+type binding struct {
+	local bool
+	// depth is the number of environments between the reference and
+	// the local declaration.
+	depth int
+}
+
+func lookUpVariable(name Token, b binding) (any, error) {
+	if b.local {
+		return environment.GetAt(b.depth, name)
+	}
+	return globals.Get(name)
+}
+
+func assignVariable(name Token, b binding, v any) error {
+	if b.local {
+		return environment.AssignAt(b.depth, name, v)
+	}
+	return globals.Assign(name, v)
 }
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 )
 
 var hadError bool
 
+// errorOutput is where Report writes compile errors.
+var errorOutput io.Writer = os.Stderr
+
 type ParseError struct{}
 
 func (p ParseError) Error() string {
@@ -18,7 +23,7 @@ func Error(line int, message string) {
 }
 
 func Report(line int, where string, message string) {
-	fmt.Fprintf(os.Stderr, "[line %d] Error%s: %s", line, where, message)
+	fmt.Fprintf(errorOutput, "[line %d] Error%s: %s\n", line, where, message)
 	hadError = true
 }
 
@@ -29,3 +34,56 @@ func TokenError(token Token, message string) {
 		Report(token.Line, fmt.Sprintf(" at '%s'", token.Lexeme), message)
 	}
 }
+
+// RuntimeError is an error raised while executing a script, which a try
+// statement can catch.  Line is the line of the token the error is
+// attributed to.
+type RuntimeError struct {
+	Line    int
+	Message string
+}
+
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("[line %d] %s", e.Line, e.Message)
+}
+
+// runtimeError attributes err to the line of token.  Errors that already
+// carry a line, thrown values and control-flow signals are returned
+// unchanged.
+func runtimeError(token Token, err error) error {
+	if err == nil || isSignal(err) {
+		return err
+	}
+
+	var r *RuntimeError
+	var t ThrowSignal
+	if errors.As(err, &r) || errors.As(err, &t) {
+		return err
+	}
+
+	return &RuntimeError{
+		Line:    token.Line,
+		Message: err.Error(),
+	}
+}
+
+// LoxError is the runtime value a catch clause receives for a runtime
+// error, exposing its message and line as properties.
+type LoxError struct {
+	message string
+	line    int
+}
+
+func (e *LoxError) Get(name Token) (any, error) {
+	switch name.Lexeme {
+	case "message":
+		return e.message, nil
+	case "line":
+		return int64(e.line), nil
+	}
+	return nil, fmt.Errorf("undefined property '%s'", name.Lexeme)
+}
+
+func (e *LoxError) String() string {
+	return fmt.Sprintf("[line %d] %s", e.line, e.message)
+}
@@ -1,6 +1,10 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"strings"
+)
 
 type Expr interface {
 	Print() string
@@ -36,13 +40,27 @@ func isEqual(l, r any) bool {
 		return false
 	}
 
+	if isNumber(l) && isNumber(r) {
+		return numbersEqual(l, r)
+	}
+
 	return l == r
 }
 
+// stringify converts a value to the text print shows for it.
+func stringify(v any) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprint(v)
+}
+
 // AssignExpr ///////////////////////////////////
 type AssignExpr struct {
 	Name  Token
 	Value Expr
+	// binding is set by the resolver
+	binding binding
 }
 
 func (expr AssignExpr) Evaluate() (any, error) {
@@ -51,11 +69,11 @@ func (expr AssignExpr) Evaluate() (any, error) {
 		return nil, err
 	}
 
-	return v, environment.Assign(expr.Name, v)
+	return v, assignVariable(expr.Name, expr.binding, v)
 }
 
 func (expr AssignExpr) Print() string {
-	return "<print-not-implemented>"
+	return Parenthesize("= "+expr.Name.Lexeme, expr.Value)
 }
 
 // BinaryExpr ///////////////////////////////////
@@ -76,78 +94,59 @@ func (expr BinaryExpr) Evaluate() (any, error) {
 		return nil, err
 	}
 
-	var leftFloat, rightFloat float64
-	var leftString, rightString string
-	var plusFloat bool
-
-	// Type Checking
-	switch expr.Op.Type {
-	case BANG_EQUAL, EQUAL_EQUAL, GREATER, GREATER_EQUAL, LESS, LESS_EQUAL, MINUS, SLASH, STAR:
-		// should be floats
-
-		if leftCast, ok := left.(float64); ok {
-			leftFloat = leftCast
-		} else {
-			return nil, fmt.Errorf("left operand of binary '%s' expression should be number: %v", expr.Op.Lexeme, left)
-		}
+	v, err := binaryOp(expr.Op, left, right)
+	return v, runtimeError(expr.Op, err)
+}
 
-		if rightCast, ok := right.(float64); ok {
-			rightFloat = rightCast
-		} else {
-			return nil, fmt.Errorf("right operand of binary '%s' expression should be number: %v", expr.Op.Lexeme, left)
+// binaryOp type checks the operands of the binary operator op and applies
+// it to them.
+func binaryOp(op Token, left, right any) (any, error) {
+	if op.Type == PLUS {
+		// should be either be numbers or strings
+		leftString, leftStringOK := left.(string)
+		rightString, rightStringOK := right.(string)
+		if leftStringOK && rightStringOK {
+			return leftString + rightString, nil
 		}
-	case PLUS:
-		// should be either be floats or strings
 
-		leftFloatCast, leftFloatOK := left.(float64)
-		rightFloatCast, rightFloatOK := right.(float64)
-
-		if leftFloatOK && rightFloatOK {
-			// use float for plus if left and right both casted to a float
-			plusFloat = true
-			leftFloat = leftFloatCast
-			rightFloat = rightFloatCast
+		if isNumber(left) && isNumber(right) {
+			return arithmetic(op, left, right)
 		}
 
-		leftStringCast, leftStringOK := left.(string)
-		rightStringCast, rightStringOK := right.(string)
-		if leftStringOK && rightStringOK {
-			plusFloat = false
-			leftString = leftStringCast
-			rightString = rightStringCast
-		}
+		return nil, fmt.Errorf("left and right operant of '+' expression should both be numbers or both be strings: %v, %v", left, right)
+	}
 
-		// If left and right are not both floats and they are not both strings,
-		// return a type error
-		if !(leftFloatOK && rightFloatOK) && !(leftStringOK && rightStringOK) {
-			return nil, fmt.Errorf("left and right operant of '+' expression should both be numbers or both be strings: %v, %v", left, right)
-		}
+	// every other operator should have numbers
+	if !isNumber(left) {
+		return nil, fmt.Errorf("left operand of binary '%s' expression should be number: %v", op.Lexeme, left)
+	}
+	if !isNumber(right) {
+		return nil, fmt.Errorf("right operand of binary '%s' expression should be number: %v", op.Lexeme, right)
 	}
 
-	switch expr.Op.Type {
+	switch op.Type {
 	case BANG_EQUAL:
 		return !isEqual(left, right), nil
 	case EQUAL_EQUAL:
 		return isEqual(left, right), nil
 	case GREATER:
-		return leftFloat > rightFloat, nil
+		return numbersLess(right, left), nil
 	case GREATER_EQUAL:
-		return leftFloat >= rightFloat, nil
+		return numbersLess(right, left) || numbersEqual(left, right), nil
 	case LESS:
-		return leftFloat < rightFloat, nil
+		return numbersLess(left, right), nil
 	case LESS_EQUAL:
-		return leftFloat <= rightFloat, nil
-	case MINUS:
-		return leftFloat - rightFloat, nil
-	case SLASH:
-		return leftFloat / rightFloat, nil
-	case STAR:
-		return leftFloat * rightFloat, nil
-	case PLUS:
-		if plusFloat {
-			return leftFloat + rightFloat, nil
-		}
-		return leftString + rightString, nil
+		return numbersLess(left, right) || numbersEqual(left, right), nil
+	case MINUS, SLASH, STAR:
+		return arithmetic(op, left, right)
+	case PERCENT:
+		return modulo(left, right)
+	case TILDE_SLASH:
+		return integerDivision(op, left, right)
+	case STAR_STAR:
+		return power(op, left, right)
+	case AMPERSAND, PIPE, CARET, LESS_LESS, GREATER_GREATER:
+		return bitwise(op, left, right)
 	}
 
 	// unreachable
@@ -160,17 +159,165 @@ func (expr BinaryExpr) Print() string {
 
 // CallExpr /////////////////////////////////////
 type CallExpr struct {
-	Callee Expr
-	Paren  Token
-	Args   []Expr
+	Callee    Expr
+	Paren     Token
+	Args      []Expr
+	NamedArgs []NamedArg
+}
+
+// NamedArg is an argument passed to a call by parameter name.
+type NamedArg struct {
+	Name  Token
+	Value Expr
 }
 
 func (expr CallExpr) Evaluate() (any, error) {
-	return nil, nil
+	callee, err := expr.Callee.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]any, 0, len(expr.Args))
+	for _, arg := range expr.Args {
+		v, err := arg.Evaluate()
+		if err != nil {
+			return nil, err
+		}
+
+		// a spread argument expands into the elements of its list
+		if _, ok := arg.(SpreadExpr); ok {
+			args = append(args, v.(*LoxList).elements...)
+		} else {
+			args = append(args, v)
+		}
+	}
+
+	namedArgs := make(map[string]any, len(expr.NamedArgs))
+	for _, arg := range expr.NamedArgs {
+		v, err := arg.Value.Evaluate()
+		if err != nil {
+			return nil, err
+		}
+		namedArgs[arg.Name.Lexeme] = v
+	}
+
+	function, ok := callee.(Callable)
+	if !ok {
+		return nil, runtimeError(expr.Paren, fmt.Errorf("can only call functions and classes: %v", callee))
+	}
+
+	args, err = bindArguments(function, args, expr.NamedArgs, namedArgs)
+	if err != nil {
+		return nil, runtimeError(expr.Paren, err)
+	}
+
+	// errors from native functions are attributed to the call
+	v, err := function.Call(args)
+	return v, runtimeError(expr.Paren, err)
 }
 
 func (expr CallExpr) Print() string {
-	return "<print-not-implemented>"
+	s := "(call " + expr.Callee.Print()
+	for _, arg := range expr.Args {
+		s += " " + arg.Print()
+	}
+	for _, arg := range expr.NamedArgs {
+		s += " " + Parenthesize(arg.Name.Lexeme+":", arg.Value)
+	}
+	return s + ")"
+}
+
+// CompoundAssignExpr ///////////////////////////
+type CompoundAssignExpr struct {
+	Target Expr
+	// Op is the binary operator applied, such as '+' for '+='.
+	Op    Token
+	Value Expr
+}
+
+// Evaluate evaluates the parts of the target only once, so a[f()] += 1
+// calls f a single time.
+func (expr CompoundAssignExpr) Evaluate() (any, error) {
+	target, err := evaluateTarget(expr.Target)
+	if err != nil {
+		return nil, runtimeError(expr.Op, err)
+	}
+
+	current, err := target.get()
+	if err != nil {
+		return nil, runtimeError(expr.Op, err)
+	}
+
+	value, err := expr.Value.Evaluate()
+	if err != nil {
+		return nil, runtimeError(expr.Op, err)
+	}
+
+	result, err := binaryOp(expr.Op, current, value)
+	if err != nil {
+		return nil, runtimeError(expr.Op, err)
+	}
+
+	return result, runtimeError(expr.Op, target.set(result))
+}
+
+func (expr CompoundAssignExpr) Print() string {
+	return Parenthesize(expr.Op.Lexeme+"=", expr.Target, expr.Value)
+}
+
+// ConditionalExpr //////////////////////////////
+type ConditionalExpr struct {
+	Condition Expr
+	Then      Expr
+	Else      Expr
+}
+
+// Evaluate only evaluates the branch picked by the condition.
+func (expr ConditionalExpr) Evaluate() (any, error) {
+	condition, err := expr.Condition.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	if isTruthy(condition) {
+		return expr.Then.Evaluate()
+	}
+	return expr.Else.Evaluate()
+}
+
+func (expr ConditionalExpr) Print() string {
+	return Parenthesize("?:", expr.Condition, expr.Then, expr.Else)
+}
+
+// FunctionExpr /////////////////////////////////
+type FunctionExpr struct {
+	Keyword Token
+	Params  []Param
+	Body    []Stmt
+}
+
+// Evaluate creates an anonymous function closing over the environment
+// the expression is evaluated in.
+func (expr FunctionExpr) Evaluate() (any, error) {
+	return &LoxFunction{
+		params:  expr.Params,
+		body:    expr.Body,
+		closure: environment,
+	}, nil
+}
+
+func (expr FunctionExpr) Print() string {
+	params := make([]string, len(expr.Params))
+	for i, param := range expr.Params {
+		params[i] = param.Name.Lexeme
+		if param.Rest {
+			params[i] = "..." + params[i]
+		}
+		if param.Default != nil {
+			params[i] += "=" + param.Default.Print()
+		}
+	}
+	return "(fun (" + strings.Join(params, " ") + "))"
 }
 
 // GetExpr //////////////////////////////////////
@@ -180,11 +327,25 @@ type GetExpr struct {
 }
 
 func (expr GetExpr) Evaluate() (any, error) {
-	return nil, nil
+	object, err := expr.Object.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	var v any
+	switch object := object.(type) {
+	case *LoxInstance:
+		v, err = object.Get(expr.Name)
+	case *LoxError:
+		v, err = object.Get(expr.Name)
+	default:
+		err = fmt.Errorf("only instances have properties: %v", object)
+	}
+	return v, runtimeError(expr.Name, err)
 }
 
 func (expr GetExpr) Print() string {
-	return "<print-not-implemented>"
+	return Parenthesize(". "+expr.Name.Lexeme, expr.Object)
 }
 
 // GroupingExpr /////////////////////////////////
@@ -200,6 +361,170 @@ func (expr GroupingExpr) Print() string {
 	return Parenthesize("group", expr.Expression)
 }
 
+// IncrementExpr ////////////////////////////////
+type IncrementExpr struct {
+	Target Expr
+	// Op is the binary operator applied, '+' for '++' and '-' for '--'.
+	Op     Token
+	Prefix bool
+}
+
+// Evaluate returns the updated value for the prefix form and the
+// original value for the postfix form.
+func (expr IncrementExpr) Evaluate() (any, error) {
+	target, err := evaluateTarget(expr.Target)
+	if err != nil {
+		return nil, runtimeError(expr.Op, err)
+	}
+
+	current, err := target.get()
+	if err != nil {
+		return nil, runtimeError(expr.Op, err)
+	}
+
+	result, err := binaryOp(expr.Op, current, int64(1))
+	if err != nil {
+		return nil, runtimeError(expr.Op, err)
+	}
+
+	if err := target.set(result); err != nil {
+		return nil, runtimeError(expr.Op, err)
+	}
+
+	if expr.Prefix {
+		return result, nil
+	}
+	return current, nil
+}
+
+func (expr IncrementExpr) Print() string {
+	if expr.Prefix {
+		return Parenthesize("pre"+expr.Op.Lexeme+expr.Op.Lexeme, expr.Target)
+	}
+	return Parenthesize("post"+expr.Op.Lexeme+expr.Op.Lexeme, expr.Target)
+}
+
+// IndexExpr ////////////////////////////////////
+type IndexExpr struct {
+	Object  Expr
+	Bracket Token
+	Index   Expr
+}
+
+func (expr IndexExpr) Evaluate() (any, error) {
+	object, err := expr.Object.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := expr.Index.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	var v any
+	switch object := object.(type) {
+	case *LoxList:
+		v, err = object.Get(index)
+	case *LoxMap:
+		v, err = object.Get(index)
+	default:
+		err = fmt.Errorf("only lists and maps can be indexed: %v", stringify(object))
+	}
+	return v, runtimeError(expr.Bracket, err)
+}
+
+func (expr IndexExpr) Print() string {
+	return Parenthesize("index", expr.Object, expr.Index)
+}
+
+// IndexSetExpr /////////////////////////////////
+type IndexSetExpr struct {
+	Object  Expr
+	Bracket Token
+	Index   Expr
+	Value   Expr
+}
+
+func (expr IndexSetExpr) Evaluate() (any, error) {
+	object, err := expr.Object.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := expr.Index.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := expr.Value.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	switch object := object.(type) {
+	case *LoxList:
+		err = object.Set(index, v)
+	case *LoxMap:
+		err = object.Set(index, v)
+	default:
+		err = fmt.Errorf("only lists and maps support index assignment: %v", stringify(object))
+	}
+	if err != nil {
+		return nil, runtimeError(expr.Bracket, err)
+	}
+	return v, nil
+}
+
+func (expr IndexSetExpr) Print() string {
+	return Parenthesize("index=", expr.Object, expr.Index, expr.Value)
+}
+
+// InterpolationExpr ////////////////////////////
+type InterpolationExpr struct {
+	Parts []Expr
+}
+
+// Evaluate concatenates every part, converting each value to a string
+// the same way print does.
+func (expr InterpolationExpr) Evaluate() (any, error) {
+	var sb strings.Builder
+	for _, part := range expr.Parts {
+		v, err := part.Evaluate()
+		if err != nil {
+			return nil, err
+		}
+		sb.WriteString(stringify(v))
+	}
+	return sb.String(), nil
+}
+
+func (expr InterpolationExpr) Print() string {
+	return Parenthesize("concat", expr.Parts...)
+}
+
+// ListExpr /////////////////////////////////////
+type ListExpr struct {
+	Bracket  Token
+	Elements []Expr
+}
+
+func (expr ListExpr) Evaluate() (any, error) {
+	elements := make([]any, 0, len(expr.Elements))
+	for _, element := range expr.Elements {
+		v, err := element.Evaluate()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, v)
+	}
+	return NewLoxList(elements), nil
+}
+
+func (expr ListExpr) Print() string {
+	return Parenthesize("list", expr.Elements...)
+}
+
 // LiteralExpr //////////////////////////////////
 type LiteralExpr struct {
 	Value any
@@ -223,12 +548,68 @@ type LogicalExpr struct {
 	Op    Token
 }
 
+// Evaluate short-circuits: the right operand is only evaluated when the
+// left operand does not already decide the result.  The deciding operand
+// itself is returned rather than a coerced bool.
 func (expr LogicalExpr) Evaluate() (any, error) {
-	return nil, nil
+	left, err := expr.Left.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	if expr.Op.Type == OR {
+		if isTruthy(left) {
+			return left, nil
+		}
+	} else {
+		if !isTruthy(left) {
+			return left, nil
+		}
+	}
+
+	return expr.Right.Evaluate()
 }
 
 func (expr LogicalExpr) Print() string {
-	return "<print-not-implemented>"
+	return Parenthesize(expr.Op.Lexeme, expr.Left, expr.Right)
+}
+
+// MapExpr //////////////////////////////////////
+type MapExpr struct {
+	Brace Token
+	Keys  []Expr
+	// Colons holds the ':' token of each entry, which errors about its key
+	// are reported at.
+	Colons []Token
+	Values []Expr
+}
+
+func (expr MapExpr) Evaluate() (any, error) {
+	m := NewLoxMap()
+	for i := range expr.Keys {
+		k, err := expr.Keys[i].Evaluate()
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := expr.Values[i].Evaluate()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := m.Set(k, v); err != nil {
+			return nil, runtimeError(expr.Colons[i], err)
+		}
+	}
+	return m, nil
+}
+
+func (expr MapExpr) Print() string {
+	entries := make([]Expr, 0, 2*len(expr.Keys))
+	for i := range expr.Keys {
+		entries = append(entries, expr.Keys[i], expr.Values[i])
+	}
+	return Parenthesize("map", entries...)
 }
 
 // SetExpr //////////////////////////////////////
@@ -239,38 +620,154 @@ type SetExpr struct {
 }
 
 func (expr SetExpr) Evaluate() (any, error) {
-	return nil, nil
+	object, err := expr.Object.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	instance, ok := object.(*LoxInstance)
+	if !ok {
+		return nil, runtimeError(expr.Name, fmt.Errorf("only instances have fields: %v", object))
+	}
+
+	v, err := expr.Value.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	instance.Set(expr.Name, v)
+	return v, nil
 }
 
 func (expr SetExpr) Print() string {
-	return "<print-not-implemented>"
+	return Parenthesize("= "+expr.Name.Lexeme, expr.Object, expr.Value)
+}
+
+// SliceExpr ////////////////////////////////////
+type SliceExpr struct {
+	Object  Expr
+	Bracket Token
+	Start   Expr
+	End     Expr
+}
+
+func (expr SliceExpr) Evaluate() (any, error) {
+	object, err := expr.Object.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	var start, end any
+	if expr.Start != nil {
+		start, err = expr.Start.Evaluate()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if expr.End != nil {
+		end, err = expr.End.Evaluate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	list, ok := object.(*LoxList)
+	if !ok {
+		return nil, runtimeError(expr.Bracket, fmt.Errorf("only lists can be sliced: %v", stringify(object)))
+	}
+
+	v, err := list.Slice(start, end)
+	return v, runtimeError(expr.Bracket, err)
+}
+
+func (expr SliceExpr) Print() string {
+	// omitted bounds print as nil
+	start, end := expr.Start, expr.End
+	if start == nil {
+		start = LiteralExpr{}
+	}
+	if end == nil {
+		end = LiteralExpr{}
+	}
+	return Parenthesize("slice", expr.Object, start, end)
+}
+
+// SpreadExpr ///////////////////////////////////
+type SpreadExpr struct {
+	Ellipsis Token
+	Expr     Expr
+}
+
+// Evaluate checks that the spread value is a list, CallExpr expands it
+// into the call's arguments.
+func (expr SpreadExpr) Evaluate() (any, error) {
+	v, err := expr.Expr.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	list, ok := v.(*LoxList)
+	if !ok {
+		return nil, runtimeError(expr.Ellipsis, fmt.Errorf("spread argument should be a list: %s", stringify(v)))
+	}
+	return list, nil
+}
+
+func (expr SpreadExpr) Print() string {
+	return Parenthesize("...", expr.Expr)
 }
 
 // SuperExpr ////////////////////////////////////
 type SuperExpr struct {
 	Keyword Token
 	Method  Token
+	// binding is set by the resolver
+	binding binding
 }
 
+// Evaluate looks the method up starting at the superclass of the class
+// the enclosing method was declared in, and binds it to the current
+// instance.
 func (expr SuperExpr) Evaluate() (any, error) {
-	return nil, nil
+	v, err := lookUpVariable(expr.Keyword, expr.binding)
+	if err != nil {
+		return nil, err
+	}
+	superclass := v.(*LoxClass)
+
+	// 'this' is defined in the environment just inside the one defining
+	// 'super'
+	v, err = environment.GetAt(expr.binding.depth-1, Token{Lexeme: "this"})
+	if err != nil {
+		return nil, err
+	}
+	instance := v.(*LoxInstance)
+
+	method, ok := superclass.findMethod(expr.Method.Lexeme)
+	if !ok {
+		return nil, runtimeError(expr.Method, fmt.Errorf("undefined property '%s'", expr.Method.Lexeme))
+	}
+
+	return method.bind(instance), nil
 }
 
 func (expr SuperExpr) Print() string {
-	return "<print-not-implemented>"
+	return Parenthesize("super " + expr.Method.Lexeme)
 }
 
 // ThisExpr /////////////////////////////////////
 type ThisExpr struct {
 	Keyword Token
+	// binding is set by the resolver
+	binding binding
 }
 
 func (expr ThisExpr) Evaluate() (any, error) {
-	return nil, nil
+	return lookUpVariable(expr.Keyword, expr.binding)
 }
 
 func (expr ThisExpr) Print() string {
-	return "<print-not-implemented>"
+	return expr.Keyword.Lexeme
 }
 
 // UnaryExpr ////////////////////////////////////
@@ -287,12 +784,22 @@ func (expr UnaryExpr) Evaluate() (any, error) {
 
 	switch expr.Op.Type {
 	case MINUS:
-		// TODO: what if right is not a float64?
-		rightFloat, ok := right.(float64)
+		switch n := right.(type) {
+		case int64:
+			if n == math.MinInt64 {
+				return nil, runtimeError(expr.Op, fmt.Errorf("integer overflow in unary '-' expression: %d", n))
+			}
+			return -n, nil
+		case float64:
+			return -n, nil
+		}
+		return nil, runtimeError(expr.Op, fmt.Errorf("operand for unary '-' expression should be a number: %v", right))
+	case TILDE:
+		n, ok := toInteger(right)
 		if !ok {
-			return nil, fmt.Errorf("operand for unary '-' expression should be a number: %v", right)
+			return nil, runtimeError(expr.Op, fmt.Errorf("operand for unary '~' expression should be an integer: %v", right))
 		}
-		return -rightFloat, nil
+		return ^n, nil
 	case BANG:
 		return !isTruthy(right), nil
 	}
@@ -305,15 +812,96 @@ func (expr UnaryExpr) Print() string {
 	return Parenthesize(expr.Op.Lexeme, expr.Right)
 }
 
+// assignTarget reads and writes the place an assignment updates.
+type assignTarget struct {
+	get func() (any, error)
+	set func(v any) error
+}
+
+// evaluateTarget evaluates the object and index of an assignable
+// expression once, returning a target that can then be read and written
+// any number of times.
+func evaluateTarget(expr Expr) (assignTarget, error) {
+	switch expr := expr.(type) {
+	case VariableExpr:
+		return assignTarget{
+			get: func() (any, error) {
+				return lookUpVariable(expr.Name, expr.binding)
+			},
+			set: func(v any) error {
+				return assignVariable(expr.Name, expr.binding, v)
+			},
+		}, nil
+	case GetExpr:
+		object, err := expr.Object.Evaluate()
+		if err != nil {
+			return assignTarget{}, err
+		}
+
+		instance, ok := object.(*LoxInstance)
+		if !ok {
+			return assignTarget{}, fmt.Errorf("only instances have fields: %v", object)
+		}
+
+		return assignTarget{
+			get: func() (any, error) {
+				return instance.Get(expr.Name)
+			},
+			set: func(v any) error {
+				instance.Set(expr.Name, v)
+				return nil
+			},
+		}, nil
+	case IndexExpr:
+		object, err := expr.Object.Evaluate()
+		if err != nil {
+			return assignTarget{}, err
+		}
+
+		index, err := expr.Index.Evaluate()
+		if err != nil {
+			return assignTarget{}, err
+		}
+
+		switch object := object.(type) {
+		case *LoxList:
+			return assignTarget{
+				get: func() (any, error) {
+					return object.Get(index)
+				},
+				set: func(v any) error {
+					return object.Set(index, v)
+				},
+			}, nil
+		case *LoxMap:
+			return assignTarget{
+				get: func() (any, error) {
+					return object.Get(index)
+				},
+				set: func(v any) error {
+					return object.Set(index, v)
+				},
+			}, nil
+		}
+
+		return assignTarget{}, fmt.Errorf("only lists and maps support index assignment: %v", stringify(object))
+	}
+
+	// unreachable, the parser only produces assignable targets
+	return assignTarget{}, fmt.Errorf("invalid assignment target: %s", expr.Print())
+}
+
 // VariableExpr /////////////////////////////////
 type VariableExpr struct {
 	Name Token
+	// binding is set by the resolver
+	binding binding
 }
 
 func (expr VariableExpr) Evaluate() (any, error) {
-	return environment.Get(expr.Name)
+	return lookUpVariable(expr.Name, expr.binding)
 }
 
 func (expr VariableExpr) Print() string {
-	return "<print-not-implemented>"
+	return expr.Name.Lexeme
 }
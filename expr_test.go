@@ -1,6 +1,7 @@
 package main
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -132,6 +133,18 @@ func TestIsEqual(t *testing.T) {
 			r:        2.,
 			expected: false,
 		},
+		{
+			name:     "integer_equal_float",
+			l:        int64(1),
+			r:        1.,
+			expected: true,
+		},
+		{
+			name:     "integer_not_equal_float",
+			l:        int64(1),
+			r:        1.5,
+			expected: false,
+		},
 		{
 			name:     "strings_equal",
 			l:        "s1",
@@ -498,3 +511,394 @@ func TestUnaryTypeError(t *testing.T) {
 		})
 	}
 }
+
+func TestIntegerArithmetic(t *testing.T) {
+	testCases := []struct {
+		name     string
+		l        any
+		r        any
+		op       TokenType
+		expected any
+	}{
+		{name: "plus", l: int64(3), r: int64(5), op: PLUS, expected: int64(8)},
+		{name: "minus", l: int64(3), r: int64(5), op: MINUS, expected: int64(-2)},
+		{name: "star", l: int64(3), r: int64(5), op: STAR, expected: int64(15)},
+		{name: "exact_slash", l: int64(15), r: int64(5), op: SLASH, expected: int64(3)},
+		{name: "inexact_slash", l: int64(7), r: int64(2), op: SLASH, expected: 3.5},
+		{name: "slash_by_zero", l: int64(1), r: int64(0), op: SLASH, expected: math.Inf(1)},
+		{name: "precise_past_float", l: int64(1 << 53), r: int64(1), op: PLUS, expected: int64(1<<53 + 1)},
+		{name: "promote_left", l: int64(3), r: 0.5, op: PLUS, expected: 3.5},
+		{name: "promote_right", l: 0.5, r: int64(3), op: STAR, expected: 1.5},
+		{name: "less", l: int64(3), r: 3.5, op: LESS, expected: true},
+		{name: "greater_equal", l: int64(3), r: 3., op: GREATER_EQUAL, expected: true},
+		{name: "equal_equal", l: int64(3), r: 3., op: EQUAL_EQUAL, expected: true},
+		{name: "greater_equal_nan", l: int64(3), r: math.NaN(), op: GREATER_EQUAL, expected: false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := BinaryExpr{
+				Op: Token{
+					Type: tt.op,
+				},
+				Left: LiteralExpr{
+					Value: tt.l,
+				},
+				Right: LiteralExpr{
+					Value: tt.r,
+				},
+			}.Evaluate()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	testCases := []struct {
+		name string
+		expr Expr
+	}{
+		{
+			name: "plus",
+			expr: BinaryExpr{
+				Op:    Token{Type: PLUS, Lexeme: "+"},
+				Left:  LiteralExpr{Value: int64(math.MaxInt64)},
+				Right: LiteralExpr{Value: int64(1)},
+			},
+		},
+		{
+			name: "minus",
+			expr: BinaryExpr{
+				Op:    Token{Type: MINUS, Lexeme: "-"},
+				Left:  LiteralExpr{Value: int64(math.MinInt64)},
+				Right: LiteralExpr{Value: int64(1)},
+			},
+		},
+		{
+			name: "star",
+			expr: BinaryExpr{
+				Op:    Token{Type: STAR, Lexeme: "*"},
+				Left:  LiteralExpr{Value: int64(1 << 32)},
+				Right: LiteralExpr{Value: int64(1 << 32)},
+			},
+		},
+		{
+			name: "star_min_int",
+			expr: BinaryExpr{
+				Op:    Token{Type: STAR, Lexeme: "*"},
+				Left:  LiteralExpr{Value: int64(math.MinInt64)},
+				Right: LiteralExpr{Value: int64(-1)},
+			},
+		},
+		{
+			name: "negate_min_int",
+			expr: UnaryExpr{
+				Op:    Token{Type: MINUS, Lexeme: "-"},
+				Right: LiteralExpr{Value: int64(math.MinInt64)},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.expr.Evaluate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "integer overflow")
+		})
+	}
+}
+
+func TestArithmeticOperators(t *testing.T) {
+	testCases := []struct {
+		name     string
+		l        any
+		r        any
+		op       TokenType
+		expected any
+	}{
+		{name: "modulo", l: int64(7), r: int64(3), op: PERCENT, expected: int64(1)},
+		{name: "modulo_negative", l: int64(-7), r: int64(3), op: PERCENT, expected: int64(-1)},
+		{name: "modulo_float", l: 7.5, r: int64(2), op: PERCENT, expected: 1.5},
+		{name: "integer_division", l: int64(7), r: int64(2), op: TILDE_SLASH, expected: int64(3)},
+		{name: "integer_division_negative", l: int64(-7), r: int64(2), op: TILDE_SLASH, expected: int64(-3)},
+		{name: "integer_division_float", l: 7.5, r: int64(2), op: TILDE_SLASH, expected: 3.},
+		{name: "power", l: int64(2), r: int64(10), op: STAR_STAR, expected: int64(1024)},
+		{name: "power_zero", l: int64(5), r: int64(0), op: STAR_STAR, expected: int64(1)},
+		{name: "power_negative_exponent", l: int64(2), r: int64(-1), op: STAR_STAR, expected: 0.5},
+		{name: "power_float", l: 4., r: 0.5, op: STAR_STAR, expected: 2.},
+		{name: "power_largest", l: int64(2), r: int64(62), op: STAR_STAR, expected: int64(1 << 62)},
+		{name: "and", l: int64(12), r: int64(10), op: AMPERSAND, expected: int64(8)},
+		{name: "or", l: int64(12), r: int64(10), op: PIPE, expected: int64(14)},
+		{name: "xor", l: int64(12), r: int64(10), op: CARET, expected: int64(6)},
+		{name: "shift_left", l: int64(1), r: int64(4), op: LESS_LESS, expected: int64(16)},
+		{name: "shift_right", l: int64(-16), r: int64(2), op: GREATER_GREATER, expected: int64(-4)},
+		{name: "shift_into_sign_bit", l: int64(-1), r: int64(63), op: LESS_LESS, expected: int64(math.MinInt64)},
+		{name: "shift_zero_past_width", l: int64(0), r: int64(70), op: LESS_LESS, expected: int64(0)},
+		{name: "integral_float_operand", l: 12., r: int64(10), op: AMPERSAND, expected: int64(8)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := BinaryExpr{
+				Op: Token{
+					Type: tt.op,
+				},
+				Left: LiteralExpr{
+					Value: tt.l,
+				},
+				Right: LiteralExpr{
+					Value: tt.r,
+				},
+			}.Evaluate()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestArithmeticOperatorErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		l      any
+		r      any
+		op     Token
+		errMsg string
+	}{
+		{
+			name:   "modulo_by_zero",
+			l:      int64(1),
+			r:      int64(0),
+			op:     Token{Type: PERCENT, Lexeme: "%"},
+			errMsg: "integer modulo by zero",
+		},
+		{
+			name:   "integer_division_by_zero",
+			l:      int64(1),
+			r:      int64(0),
+			op:     Token{Type: TILDE_SLASH, Lexeme: "~/"},
+			errMsg: "integer division by zero",
+		},
+		{
+			name:   "integer_division_overflow",
+			l:      int64(math.MinInt64),
+			r:      int64(-1),
+			op:     Token{Type: TILDE_SLASH, Lexeme: "~/"},
+			errMsg: "integer overflow in binary '~/' expression",
+		},
+		{
+			name:   "shift_overflow",
+			l:      int64(1),
+			r:      int64(63),
+			op:     Token{Type: LESS_LESS, Lexeme: "<<"},
+			errMsg: "integer overflow in binary '<<' expression: 1, 63",
+		},
+		{
+			name:   "shift_past_width",
+			l:      int64(1),
+			r:      int64(64),
+			op:     Token{Type: LESS_LESS, Lexeme: "<<"},
+			errMsg: "integer overflow in binary '<<' expression: 1, 64",
+		},
+		{
+			name:   "power_overflow",
+			l:      int64(2),
+			r:      int64(63),
+			op:     Token{Type: STAR_STAR, Lexeme: "**"},
+			errMsg: "integer overflow in binary '**' expression: 2, 63",
+		},
+		{
+			name:   "non_integral_left",
+			l:      1.5,
+			r:      int64(1),
+			op:     Token{Type: PIPE, Lexeme: "|"},
+			errMsg: "left operand of binary '|' expression should be an integer: 1.5",
+		},
+		{
+			name:   "non_integral_right",
+			l:      int64(1),
+			r:      0.5,
+			op:     Token{Type: LESS_LESS, Lexeme: "<<"},
+			errMsg: "right operand of binary '<<' expression should be an integer: 0.5",
+		},
+		{
+			name:   "non_number",
+			l:      "s",
+			r:      int64(1),
+			op:     Token{Type: CARET, Lexeme: "^"},
+			errMsg: "left operand of binary '^' expression should be number: s",
+		},
+		{
+			name:   "negative_shift",
+			l:      int64(1),
+			r:      int64(-1),
+			op:     Token{Type: GREATER_GREATER, Lexeme: ">>"},
+			errMsg: "shift count of binary '>>' expression should not be negative: -1",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BinaryExpr{
+				Op:    tt.op,
+				Left:  LiteralExpr{Value: tt.l},
+				Right: LiteralExpr{Value: tt.r},
+			}.Evaluate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestBitwiseNot(t *testing.T) {
+	v, err := UnaryExpr{
+		Op:    Token{Type: TILDE, Lexeme: "~"},
+		Right: LiteralExpr{Value: int64(5)},
+	}.Evaluate()
+	require.NoError(t, err)
+	assert.Equal(t, int64(-6), v)
+
+	_, err = UnaryExpr{
+		Op:    Token{Type: TILDE, Lexeme: "~"},
+		Right: LiteralExpr{Value: 0.5},
+	}.Evaluate()
+	assert.ErrorContains(t, err, "operand for unary '~' expression should be an integer: 0.5")
+}
+
+func TestLogicalEvaluate(t *testing.T) {
+	// erroring fails the test if it is ever evaluated
+	erroring := UnaryExpr{
+		Op: Token{
+			Type: MINUS,
+		},
+		Right: LiteralExpr{
+			Value: "not a number",
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		op       TokenType
+		left     Expr
+		right    Expr
+		expected any
+	}{
+		{
+			name:     "or_returns_truthy_left",
+			op:       OR,
+			left:     LiteralExpr{Value: "left"},
+			right:    erroring,
+			expected: "left",
+		},
+		{
+			name:     "or_returns_right",
+			op:       OR,
+			left:     LiteralExpr{Value: nil},
+			right:    LiteralExpr{Value: "right"},
+			expected: "right",
+		},
+		{
+			name:     "and_returns_falsey_left",
+			op:       AND,
+			left:     LiteralExpr{Value: false},
+			right:    erroring,
+			expected: false,
+		},
+		{
+			name:     "and_returns_right",
+			op:       AND,
+			left:     LiteralExpr{Value: 1.},
+			right:    LiteralExpr{Value: 2.},
+			expected: 2.,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := LogicalExpr{
+				Op: Token{
+					Type: tt.op,
+				},
+				Left:  tt.left,
+				Right: tt.right,
+			}.Evaluate()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestLogicalPrint(t *testing.T) {
+	expr := LogicalExpr{
+		Op: Token{
+			Type:   OR,
+			Lexeme: "or",
+		},
+		Left: LiteralExpr{
+			Value: nil,
+		},
+		Right: LogicalExpr{
+			Op: Token{
+				Type:   AND,
+				Lexeme: "and",
+			},
+			Left: LiteralExpr{
+				Value: true,
+			},
+			Right: LiteralExpr{
+				Value: "s",
+			},
+		},
+	}
+	assert.Equal(t, "(or nil (and true s))", expr.Print())
+}
+
+func TestStringify(t *testing.T) {
+	testCases := []struct {
+		name     string
+		v        any
+		expected string
+	}{
+		{
+			name:     "nil",
+			v:        nil,
+			expected: "nil",
+		},
+		{
+			name:     "integral_float",
+			v:        3.,
+			expected: "3",
+		},
+		{
+			name:     "float",
+			v:        2.5,
+			expected: "2.5",
+		},
+		{
+			name:     "large_integer",
+			v:        int64(9007199254740993),
+			expected: "9007199254740993",
+		},
+		{
+			name:     "string",
+			v:        "s",
+			expected: "s",
+		},
+		{
+			name:     "bool",
+			v:        true,
+			expected: "true",
+		},
+		{
+			name:     "native",
+			v:        natives[0],
+			expected: "<native fn clock>",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, stringify(tt.v))
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// maxArgs is the maximum number of parameters a function may declare
+// and the maximum number of arguments a call may pass.
+const maxArgs = 255
+
+// Callable is implemented by any value that can be invoked with a
+// CallExpr.  Arity is the number of arguments a call has to pass, the
+// callable may accept more when it has parameters with defaults or a rest
+// parameter.
+type Callable interface {
+	Arity() int
+	Call(args []any) (any, error)
+}
+
+// Param is a declared function parameter.  Default is nil for a
+// required parameter.  A rest parameter can only be the last parameter,
+// and collects the extra positional arguments into a list.
+type Param struct {
+	Name    Token
+	Default Expr
+	Rest    bool
+}
+
+// parameterized is implemented by callables whose parameters have names
+// and may have default values, so they accept named arguments.
+type parameterized interface {
+	parameters() []Param
+}
+
+// missingArgument fills the argument slot of a parameter that was not
+// passed, for the function to evaluate its default value.
+type missingArgument struct{}
+
+// bindArguments matches the positional and named arguments of a call to
+// the parameters of function, returning one argument per parameter.  A
+// rest parameter is passed a list of the extra positional arguments.
+// Callables without named parameters only take exactly Arity positional
+// arguments.
+func bindArguments(function Callable, args []any, names []NamedArg, namedArgs map[string]any) ([]any, error) {
+	p, ok := function.(parameterized)
+	if !ok {
+		if len(names) > 0 {
+			return nil, fmt.Errorf("%v does not take named arguments", function)
+		}
+		if len(args) != function.Arity() {
+			return nil, fmt.Errorf("expected %d arguments but got %d", function.Arity(), len(args))
+		}
+		return args, nil
+	}
+
+	params := p.parameters()
+	var rest *Param
+	if n := len(params); n > 0 && params[n-1].Rest {
+		rest = &params[n-1]
+		params = params[:n-1]
+	}
+
+	if len(args) > len(params) && rest == nil {
+		required := 0
+		for _, param := range params {
+			if param.Default == nil {
+				required++
+			}
+		}
+		if required == len(params) {
+			return nil, fmt.Errorf("expected %d arguments but got %d", len(params), len(args))
+		}
+		return nil, fmt.Errorf("expected at most %d arguments but got %d", len(params), len(args))
+	}
+
+	bound := make([]any, len(params))
+	for i := range bound {
+		if i < len(args) {
+			bound[i] = args[i]
+		} else {
+			bound[i] = missingArgument{}
+		}
+	}
+
+	for _, name := range names {
+		if rest != nil && rest.Name.Lexeme == name.Name.Lexeme {
+			return nil, fmt.Errorf("rest parameter '%s' can't be passed by name", name.Name.Lexeme)
+		}
+		i := slices.IndexFunc(params, func(param Param) bool {
+			return param.Name.Lexeme == name.Name.Lexeme
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("unknown parameter '%s' for %v", name.Name.Lexeme, function)
+		}
+		if i < len(args) {
+			return nil, fmt.Errorf("parameter '%s' was passed both positionally and by name", name.Name.Lexeme)
+		}
+		bound[i] = namedArgs[name.Name.Lexeme]
+	}
+
+	for i, param := range params {
+		if _, missing := bound[i].(missingArgument); missing && param.Default == nil {
+			return nil, fmt.Errorf("missing argument for parameter '%s'", param.Name.Lexeme)
+		}
+	}
+
+	if rest != nil {
+		extra := []any{}
+		if len(args) > len(params) {
+			extra = append(extra, args[len(params):]...)
+		}
+		bound = append(bound, NewLoxList(extra))
+	}
+
+	return bound, nil
+}
+
+// ReturnValue carries the value of a return statement up through the
+// executing statements until it reaches the enclosing function call.
+type ReturnValue struct {
+	Value any
+}
+
+func (r ReturnValue) Error() string {
+	return "return outside of function"
+}
+
+// LoxFunction is a user-defined function, method or lambda.  closure is
+// the environment active where the function was declared, which the body
+// executes in regardless of where the function is eventually called from.
+// Lambdas have an empty name.
+type LoxFunction struct {
+	name          string
+	params        []Param
+	body          []Stmt
+	closure       *Environment
+	isInitializer bool
+}
+
+// Arity is the number of required parameters, those without a default
+// that are not a rest parameter.
+func (f *LoxFunction) Arity() int {
+	required := 0
+	for _, param := range f.params {
+		if param.Default == nil && !param.Rest {
+			required++
+		}
+	}
+	return required
+}
+
+func (f *LoxFunction) parameters() []Param {
+	return f.params
+}
+
+// Call runs the function with one argument per parameter.  Defaults of
+// parameters that were not passed are evaluated in the function's
+// environment, so they can refer to the parameters before them.
+func (f *LoxFunction) Call(args []any) (any, error) {
+	env := NewEnvironment(f.closure)
+	for i, param := range f.params {
+		v := args[i]
+		if _, missing := v.(missingArgument); missing {
+			var err error
+			v, err = evaluateIn(param.Default, env)
+			if err != nil {
+				return nil, err
+			}
+		}
+		env.Define(param.Name.Lexeme, v)
+	}
+
+	err := executeBlock(f.body, env)
+
+	var r ReturnValue
+	if errors.As(err, &r) {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// initializers always return 'this', even from an early return
+	if f.isInitializer {
+		return f.closure.Get(Token{Lexeme: "this"})
+	}
+	return r.Value, nil
+}
+
+// bind returns a copy of the method whose closure defines 'this' as the
+// given instance.
+func (f *LoxFunction) bind(instance *LoxInstance) *LoxFunction {
+	env := NewEnvironment(f.closure)
+	env.Define("this", instance)
+
+	bound := *f
+	bound.closure = env
+	return &bound
+}
+
+func (f *LoxFunction) String() string {
+	if f.name == "" {
+		return "<fn>"
+	}
+	return fmt.Sprintf("<fn %s>", f.name)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LoxList is the runtime value of a list.  Lists are shared by
+// reference, so it is always handled through a pointer.
+type LoxList struct {
+	elements []any
+}
+
+func NewLoxList(elements []any) *LoxList {
+	return &LoxList{
+		elements: elements,
+	}
+}
+
+// Get returns the element at index, counting from the end of the list
+// when index is negative.
+func (l *LoxList) Get(index any) (any, error) {
+	i, err := l.index(index)
+	if err != nil {
+		return nil, err
+	}
+	return l.elements[i], nil
+}
+
+// Set replaces the element at index, counting from the end of the list
+// when index is negative.
+func (l *LoxList) Set(index any, v any) error {
+	i, err := l.index(index)
+	if err != nil {
+		return err
+	}
+	l.elements[i] = v
+	return nil
+}
+
+// Slice returns a new list holding the elements from start up to but not
+// including end.  Either bound may be nil to mean the start or end of the
+// list, and negative bounds count from the end of the list.
+func (l *LoxList) Slice(start, end any) (*LoxList, error) {
+	from, to := 0, len(l.elements)
+
+	if start != nil {
+		i, err := l.bound(start)
+		if err != nil {
+			return nil, err
+		}
+		from = i
+	}
+
+	if end != nil {
+		i, err := l.bound(end)
+		if err != nil {
+			return nil, err
+		}
+		to = i
+	}
+
+	if from > to {
+		return nil, fmt.Errorf("slice start %s is after slice end %s", stringify(start), stringify(end))
+	}
+
+	elements := make([]any, to-from)
+	copy(elements, l.elements[from:to])
+	return NewLoxList(elements), nil
+}
+
+// index converts a Lox index into a position in the list.
+func (l *LoxList) index(index any) (int, error) {
+	i, ok := toIndex(index)
+	if !ok {
+		return 0, fmt.Errorf("list index should be an integer: %s", stringify(index))
+	}
+
+	if i < 0 {
+		i += len(l.elements)
+	}
+
+	if i < 0 || i >= len(l.elements) {
+		return 0, fmt.Errorf("list index %s out of range for list of length %d", stringify(index), len(l.elements))
+	}
+	return i, nil
+}
+
+// bound converts a Lox slice bound into a position in the list.  Unlike
+// an index, a bound may refer to the position just past the last element.
+func (l *LoxList) bound(bound any) (int, error) {
+	i, ok := toIndex(bound)
+	if !ok {
+		return 0, fmt.Errorf("slice bound should be an integer: %s", stringify(bound))
+	}
+
+	if i < 0 {
+		i += len(l.elements)
+	}
+
+	if i < 0 || i > len(l.elements) {
+		return 0, fmt.Errorf("slice bound %s out of range for list of length %d", stringify(bound), len(l.elements))
+	}
+	return i, nil
+}
+
+func (l *LoxList) String() string {
+	elements := make([]string, len(l.elements))
+	for i, element := range l.elements {
+		elements[i] = stringify(element)
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// toIndex converts an integer, or a float with an integral value, into
+// an int.
+func toIndex(v any) (int, bool) {
+	n, ok := toInteger(v)
+	return int(n), ok
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListGet(t *testing.T) {
+	l := NewLoxList([]any{"a", "b", "c"})
+
+	testCases := []struct {
+		name     string
+		index    any
+		expected any
+		errMsg   string
+	}{
+		{
+			name:     "first",
+			index:    0.,
+			expected: "a",
+		},
+		{
+			name:     "last",
+			index:    2.,
+			expected: "c",
+		},
+		{
+			name:     "negative",
+			index:    -1.,
+			expected: "c",
+		},
+		{
+			name:     "negative_first",
+			index:    -3.,
+			expected: "a",
+		},
+		{
+			name:   "out_of_range",
+			index:  3.,
+			errMsg: "list index 3 out of range for list of length 3",
+		},
+		{
+			name:   "negative_out_of_range",
+			index:  -4.,
+			errMsg: "list index -4 out of range for list of length 3",
+		},
+		{
+			name:   "fractional",
+			index:  1.5,
+			errMsg: "list index should be an integer: 1.5",
+		},
+		{
+			name:   "string",
+			index:  "1",
+			errMsg: "list index should be an integer: 1",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := l.Get(tt.index)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.errMsg, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestListSlice(t *testing.T) {
+	l := NewLoxList([]any{0., 1., 2., 3.})
+
+	testCases := []struct {
+		name     string
+		start    any
+		end      any
+		expected []any
+		errMsg   string
+	}{
+		{
+			name:     "middle",
+			start:    1.,
+			end:      3.,
+			expected: []any{1., 2.},
+		},
+		{
+			name:     "open_start",
+			end:      2.,
+			expected: []any{0., 1.},
+		},
+		{
+			name:     "open_end",
+			start:    2.,
+			expected: []any{2., 3.},
+		},
+		{
+			name:     "negative",
+			start:    -3.,
+			end:      -1.,
+			expected: []any{1., 2.},
+		},
+		{
+			name:     "empty",
+			start:    2.,
+			end:      2.,
+			expected: []any{},
+		},
+		{
+			name:   "end_out_of_range",
+			start:  0.,
+			end:    5.,
+			errMsg: "slice bound 5 out of range for list of length 4",
+		},
+		{
+			name:   "start_after_end",
+			start:  3.,
+			end:    1.,
+			errMsg: "slice start 3 is after slice end 1",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := l.Slice(tt.start, tt.end)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.errMsg, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v.elements)
+		})
+	}
+}
+
+func TestListString(t *testing.T) {
+	l := NewLoxList([]any{1., "two", nil, NewLoxList([]any{true})})
+	assert.Equal(t, "[1, two, nil, [true]]", l.String())
+}
@@ -8,7 +8,8 @@ import (
 )
 
 var (
-	environment = NewEnvironment(nil)
+	globals     = newGlobals()
+	environment = globals
 )
 
 func main() {
@@ -81,6 +82,11 @@ func run(s string) error {
 		return fmt.Errorf("failed to parse")
 	}
 
+	NewResolver().Resolve(stmts)
+	if hadError {
+		return fmt.Errorf("failed to resolve")
+	}
+
 	interpret(stmts)
 
 	return nil
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// LoxMap is the runtime value of a map.  Keys are compared with isEqual
+// semantics and iterated in insertion order so output is deterministic.
+// Maps are shared by reference, so a map is always handled through a
+// pointer.
+type LoxMap struct {
+	keys   []any
+	values map[any]any
+}
+
+func NewLoxMap() *LoxMap {
+	return &LoxMap{
+		keys:   []any{},
+		values: make(map[any]any),
+	}
+}
+
+func (m *LoxMap) Get(key any) (any, error) {
+	k, err := mapKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := m.values[k]
+	if !ok {
+		return nil, fmt.Errorf("undefined map key: %s", stringify(key))
+	}
+	return v, nil
+}
+
+// Set adds or replaces the value for key.  Replacing a value keeps the
+// key's original position in the iteration order.
+func (m *LoxMap) Set(key any, v any) error {
+	k, err := mapKey(key)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := m.values[k]; !ok {
+		m.keys = append(m.keys, k)
+	}
+	m.values[k] = v
+	return nil
+}
+
+func (m *LoxMap) Has(key any) (bool, error) {
+	k, err := mapKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := m.values[k]
+	return ok, nil
+}
+
+// Delete removes key from the map, reporting whether it was present.
+func (m *LoxMap) Delete(key any) (bool, error) {
+	k, err := mapKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := m.values[k]; !ok {
+		return false, nil
+	}
+
+	delete(m.values, k)
+	m.keys = slices.DeleteFunc(m.keys, func(existing any) bool {
+		return isEqual(existing, k)
+	})
+	return true, nil
+}
+
+// Keys returns the map's keys in insertion order.
+func (m *LoxMap) Keys() []any {
+	return slices.Clone(m.keys)
+}
+
+// Values returns the map's values in key insertion order.
+func (m *LoxMap) Values() []any {
+	values := make([]any, len(m.keys))
+	for i, k := range m.keys {
+		values[i] = m.values[k]
+	}
+	return values
+}
+
+func (m *LoxMap) String() string {
+	entries := make([]string, len(m.keys))
+	for i, k := range m.keys {
+		entries[i] = stringify(k) + ": " + stringify(m.values[k])
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// mapKey checks that v can be used as a map key, only values compared
+// by value under isEqual can.  NaN is not equal to itself so it can't be
+// a key.  Floats with an integral value are stored as integers so that
+// m[1] and m[1.0] are the same entry.
+func mapKey(v any) (any, error) {
+	switch k := v.(type) {
+	case float64:
+		if n, ok := toInteger(k); ok {
+			return n, nil
+		}
+		if !math.IsNaN(k) {
+			return k, nil
+		}
+	case nil, bool, int64, string:
+		return v, nil
+	}
+	return nil, fmt.Errorf("map key should be a string, number, boolean or nil: %s", stringify(v))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapKeys(t *testing.T) {
+	m := NewLoxMap()
+	keys := []any{"s", int64(1), 2.5, true, false, nil}
+	for i, k := range keys {
+		require.NoError(t, m.Set(k, float64(i)))
+	}
+
+	for i, k := range keys {
+		v, err := m.Get(k)
+		require.NoError(t, err)
+		assert.Equal(t, float64(i), v)
+	}
+	assert.Equal(t, keys, m.Keys())
+
+	// an integral float is the same key as the integer
+	v, err := m.Get(1.)
+	require.NoError(t, err)
+	assert.Equal(t, 1., v)
+
+	err = m.Set(NewLoxList(nil), 1.)
+	assert.EqualError(t, err, "map key should be a string, number, boolean or nil: []")
+
+	err = m.Set(math.NaN(), 1.)
+	assert.EqualError(t, err, "map key should be a string, number, boolean or nil: NaN")
+	_, err = m.Get(math.NaN())
+	assert.Error(t, err)
+	assert.Len(t, m.Keys(), len(keys))
+
+	_, err = m.Get("missing")
+	assert.EqualError(t, err, "undefined map key: missing")
+}
+
+func TestMapInsertionOrder(t *testing.T) {
+	m := NewLoxMap()
+	require.NoError(t, m.Set("c", 1.))
+	require.NoError(t, m.Set("a", 2.))
+	require.NoError(t, m.Set("b", 3.))
+
+	// replacing keeps the original position
+	require.NoError(t, m.Set("c", 4.))
+	assert.Equal(t, []any{"c", "a", "b"}, m.Keys())
+	assert.Equal(t, []any{4., 2., 3.}, m.Values())
+
+	// deleting and re-inserting moves the key to the end
+	deleted, err := m.Delete("c")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	require.NoError(t, m.Set("c", 5.))
+	assert.Equal(t, []any{"a", "b", "c"}, m.Keys())
+	assert.Equal(t, "{a: 2, b: 3, c: 5}", m.String())
+
+	deleted, err = m.Delete("missing")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
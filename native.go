@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// NativeFunction is a built-in function implemented in Go and exposed to
+// scripts through the global environment.
+type NativeFunction struct {
+	name  string
+	arity int
+	fn    func(args []any) (any, error)
+}
+
+func (n *NativeFunction) Arity() int {
+	return n.arity
+}
+
+func (n *NativeFunction) Call(args []any) (any, error) {
+	return n.fn(args)
+}
+
+func (n *NativeFunction) String() string {
+	return fmt.Sprintf("<native fn %s>", n.name)
+}
+
+// natives is the registry of built-in functions defined in every global
+// environment.
+var natives = []*NativeFunction{
+	{
+		name:  "clock",
+		arity: 0,
+		fn: func(args []any) (any, error) {
+			return float64(time.Now().UnixNano()) / float64(time.Second), nil
+		},
+	},
+	{
+		name:  "len",
+		arity: 1,
+		fn: func(args []any) (any, error) {
+			switch v := args[0].(type) {
+			case string:
+				return int64(utf8.RuneCountInString(v)), nil
+			case *LoxList:
+				return int64(len(v.elements)), nil
+			case *LoxMap:
+				return int64(len(v.keys)), nil
+			}
+			return nil, fmt.Errorf("len() argument should be a string, list or map: %v", stringify(args[0]))
+		},
+	},
+	{
+		name:  "str",
+		arity: 1,
+		fn: func(args []any) (any, error) {
+			return stringify(args[0]), nil
+		},
+	},
+	{
+		name:  "num",
+		arity: 1,
+		fn: func(args []any) (any, error) {
+			switch v := args[0].(type) {
+			case int64, float64:
+				return v, nil
+			case string:
+				text := strings.TrimSpace(v)
+				if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+					return n, nil
+				}
+				n, err := strconv.ParseFloat(text, 64)
+				if err != nil {
+					return nil, fmt.Errorf("num() could not convert string to number: %q", v)
+				}
+				return n, nil
+			}
+			return nil, fmt.Errorf("num() argument should be a string or number: %v", stringify(args[0]))
+		},
+	},
+	{
+		name:  "has",
+		arity: 2,
+		fn: func(args []any) (any, error) {
+			m, err := mapArgument("has", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return m.Has(args[1])
+		},
+	},
+	{
+		name:  "delete",
+		arity: 2,
+		fn: func(args []any) (any, error) {
+			m, err := mapArgument("delete", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return m.Delete(args[1])
+		},
+	},
+	{
+		name:  "keys",
+		arity: 1,
+		fn: func(args []any) (any, error) {
+			m, err := mapArgument("keys", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return NewLoxList(m.Keys()), nil
+		},
+	},
+	{
+		name:  "values",
+		arity: 1,
+		fn: func(args []any) (any, error) {
+			m, err := mapArgument("values", args[0])
+			if err != nil {
+				return nil, err
+			}
+			return NewLoxList(m.Values()), nil
+		},
+	},
+}
+
+func mapArgument(name string, v any) (*LoxMap, error) {
+	m, ok := v.(*LoxMap)
+	if !ok {
+		return nil, fmt.Errorf("%s() argument should be a map: %v", name, stringify(v))
+	}
+	return m, nil
+}
+
+// newGlobals returns a global environment with every native function
+// defined.
+func newGlobals() *Environment {
+	env := NewEnvironment(nil)
+	for _, native := range natives {
+		env.Define(native.name, native)
+	}
+	return env
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNatives(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "len",
+			source:   `var r = len("héllo");`,
+			expected: int64(5),
+		},
+		{
+			name:     "str_number",
+			source:   `var r = str(1.5) + "!";`,
+			expected: "1.5!",
+		},
+		{
+			name:     "str_nil",
+			source:   `var r = str(nil);`,
+			expected: "nil",
+		},
+		{
+			name:     "str_function",
+			source:   `fun f() {} var r = str(f);`,
+			expected: "<fn f>",
+		},
+		{
+			name:     "num_string",
+			source:   `var r = num(" 42.5 ") + 1;`,
+			expected: 43.5,
+		},
+		{
+			name:     "num_integer_string",
+			source:   `var r = num("9007199254740993");`,
+			expected: int64(9007199254740993),
+		},
+		{
+			name:     "num_number",
+			source:   `var r = num(3);`,
+			expected: int64(3),
+		},
+		{
+			name:     "clock_advances",
+			source:   `var start = clock(); var r = clock() >= start;`,
+			expected: true,
+		},
+		{
+			name:     "shadowed",
+			source:   `fun len(s) { return "mine"; } var r = len("x");`,
+			expected: "mine",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestNativeErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{
+			name:   "arity",
+			source: `clock(1);`,
+			errMsg: "expected 0 arguments but got 1",
+		},
+		{
+			name:   "len_non_string",
+			source: `len(1);`,
+			errMsg: "len() argument should be a string, list or map",
+		},
+		{
+			name:   "num_invalid_string",
+			source: `num("abc");`,
+			errMsg: "num() could not convert string to number",
+		},
+		{
+			name:   "num_bool",
+			source: `num(true);`,
+			errMsg: "num() argument should be a string or number",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeErr(t, tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Numbers are either int64 integers or float64 floats.  Integer literals
+// produce integers and arithmetic between integers stays exact, reporting
+// overflow as an error.  As soon as a float is involved the integer is
+// promoted and float arithmetic is used.
+
+func isNumber(v any) bool {
+	switch v.(type) {
+	case int64, float64:
+		return true
+	}
+	return false
+}
+
+// toFloat converts a number of either kind to a float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// toInteger converts an integer, or a float with an integral value that
+// fits in an int64, to an int64.
+func toInteger(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case float64:
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	}
+	return 0, false
+}
+
+// numbersEqual compares two numbers by value regardless of their kind.
+func numbersEqual(l, r any) bool {
+	li, lok := l.(int64)
+	ri, rok := r.(int64)
+	if lok && rok {
+		return li == ri
+	}
+
+	lf, _ := toFloat(l)
+	rf, _ := toFloat(r)
+	return lf == rf
+}
+
+// numbersLess reports whether the number l is less than the number r.
+func numbersLess(l, r any) bool {
+	li, lok := l.(int64)
+	ri, rok := r.(int64)
+	if lok && rok {
+		return li < ri
+	}
+
+	lf, _ := toFloat(l)
+	rf, _ := toFloat(r)
+	return lf < rf
+}
+
+// arithmetic applies the arithmetic operator op to two numbers.
+func arithmetic(op Token, left, right any) (any, error) {
+	l, lok := left.(int64)
+	r, rok := right.(int64)
+	if lok && rok {
+		return integerArithmetic(op, l, r)
+	}
+
+	lf, _ := toFloat(left)
+	rf, _ := toFloat(right)
+	switch op.Type {
+	case PLUS:
+		return lf + rf, nil
+	case MINUS:
+		return lf - rf, nil
+	case STAR:
+		return lf * rf, nil
+	case SLASH:
+		return lf / rf, nil
+	}
+
+	// unreachable
+	return nil, nil
+}
+
+// integerArithmetic applies the arithmetic operator op to two integers.
+// Division only produces an integer when it is exact, otherwise the
+// result is promoted to a float so that 7 / 2 is still 3.5.
+func integerArithmetic(op Token, l, r int64) (any, error) {
+	switch op.Type {
+	case PLUS:
+		sum := l + r
+		if (sum > l) != (r > 0) {
+			return nil, integerOverflow(op, l, r)
+		}
+		return sum, nil
+	case MINUS:
+		diff := l - r
+		if (diff < l) != (r > 0) {
+			return nil, integerOverflow(op, l, r)
+		}
+		return diff, nil
+	case STAR:
+		product, ok := multiplyIntegers(l, r)
+		if !ok {
+			return nil, integerOverflow(op, l, r)
+		}
+		return product, nil
+	case SLASH:
+		if r != 0 && l%r == 0 && !(l == math.MinInt64 && r == -1) {
+			return l / r, nil
+		}
+		return float64(l) / float64(r), nil
+	}
+
+	// unreachable
+	return nil, nil
+}
+
+// multiplyIntegers multiplies two integers, reporting false if the
+// product overflows.
+func multiplyIntegers(l, r int64) (int64, bool) {
+	if l == 0 || r == 0 {
+		return 0, true
+	}
+	product := l * r
+	if product/r != l || (l == -1 && r == math.MinInt64) || (r == -1 && l == math.MinInt64) {
+		return 0, false
+	}
+	return product, true
+}
+
+// integerDivision divides two numbers truncating toward zero, so that
+// l == (l ~/ r) * r + l % r.  Two integers give an integer, with a float
+// operand the truncated quotient is a float.
+func integerDivision(op Token, left, right any) (any, error) {
+	l, lok := left.(int64)
+	r, rok := right.(int64)
+	if lok && rok {
+		if r == 0 {
+			return nil, fmt.Errorf("integer division by zero: %d ~/ %d", l, r)
+		}
+		if l == math.MinInt64 && r == -1 {
+			return nil, integerOverflow(op, l, r)
+		}
+		return l / r, nil
+	}
+
+	lf, _ := toFloat(left)
+	rf, _ := toFloat(right)
+	return math.Trunc(lf / rf), nil
+}
+
+// modulo returns the remainder of dividing two numbers, which has the
+// sign of the dividend.
+func modulo(left, right any) (any, error) {
+	l, lok := left.(int64)
+	r, rok := right.(int64)
+	if lok && rok {
+		if r == 0 {
+			return nil, fmt.Errorf("integer modulo by zero: %d %% %d", l, r)
+		}
+		return l % r, nil
+	}
+
+	lf, _ := toFloat(left)
+	rf, _ := toFloat(right)
+	return math.Mod(lf, rf), nil
+}
+
+// power raises a number to a power.  An integer raised to a non-negative
+// integer stays an integer, a negative exponent produces a float.
+func power(op Token, left, right any) (any, error) {
+	base, lok := left.(int64)
+	exponent, rok := right.(int64)
+	if !lok || !rok || exponent < 0 {
+		lf, _ := toFloat(left)
+		rf, _ := toFloat(right)
+		return math.Pow(lf, rf), nil
+	}
+
+	result := int64(1)
+	for e, b := exponent, base; e > 0; e >>= 1 {
+		var ok bool
+		if e&1 == 1 {
+			if result, ok = multiplyIntegers(result, b); !ok {
+				return nil, integerOverflow(op, base, exponent)
+			}
+		}
+		if e > 1 {
+			if b, ok = multiplyIntegers(b, b); !ok {
+				return nil, integerOverflow(op, base, exponent)
+			}
+		}
+	}
+	return result, nil
+}
+
+// bitwise applies a bitwise or shift operator to two numbers, which
+// should both have integral values.
+func bitwise(op Token, left, right any) (any, error) {
+	l, ok := toInteger(left)
+	if !ok {
+		return nil, fmt.Errorf("left operand of binary '%s' expression should be an integer: %v", op.Lexeme, left)
+	}
+	r, ok := toInteger(right)
+	if !ok {
+		return nil, fmt.Errorf("right operand of binary '%s' expression should be an integer: %v", op.Lexeme, right)
+	}
+
+	switch op.Type {
+	case AMPERSAND:
+		return l & r, nil
+	case PIPE:
+		return l | r, nil
+	case CARET:
+		return l ^ r, nil
+	case LESS_LESS, GREATER_GREATER:
+		if r < 0 {
+			return nil, fmt.Errorf("shift count of binary '%s' expression should not be negative: %d", op.Lexeme, r)
+		}
+		if op.Type == GREATER_GREATER {
+			return l >> r, nil
+		}
+		// a left shift is a multiplication by a power of two, so shifting
+		// out bits that change the value overflows
+		if r >= 64 {
+			if l != 0 {
+				return nil, integerOverflow(op, l, r)
+			}
+			return int64(0), nil
+		}
+		shifted := l << r
+		if shifted>>r != l {
+			return nil, integerOverflow(op, l, r)
+		}
+		return shifted, nil
+	}
+
+	// unreachable
+	return nil, nil
+}
+
+func integerOverflow(op Token, l, r int64) error {
+	return fmt.Errorf("integer overflow in binary '%s' expression: %d, %d", op.Lexeme, l, r)
+}
@@ -2,11 +2,16 @@ package main
 
 import (
 	"fmt"
+	"slices"
 )
 
 type Parser struct {
 	tokens  []Token
 	current int
+
+	// loops holds the label of each loop enclosing the statement being
+	// parsed, innermost last.  Unlabeled loops have an empty label.
+	loops []string
 }
 
 func NewParser(tokens []Token) (*Parser, error) {
@@ -60,6 +65,13 @@ func (p *Parser) check(tokenType TokenType) bool {
 	return p.peek().Type == tokenType
 }
 
+func (p *Parser) checkNext(tokenType TokenType) bool {
+	if p.isAtEnd() {
+		return false
+	}
+	return p.tokens[p.current+1].Type == tokenType
+}
+
 func (p *Parser) advance() Token {
 	if !p.isAtEnd() {
 		p.current++
@@ -93,13 +105,163 @@ func (p *Parser) error(token Token, message string) error {
 }
 
 func (p *Parser) declaration() (Stmt, error) {
-	if p.match(VAR) {
+	if p.match(CLASS) {
+		return p.classDeclaration()
+	} else if p.check(FUN) && p.checkNext(IDENTIFIER) {
+		// a 'fun' not followed by a name starts a lambda expression
+		p.advance()
+		return p.function("function")
+	} else if p.match(VAR) {
 		return p.varDeclaration()
 	} else {
 		return p.statement()
 	}
 }
 
+func (p *Parser) classDeclaration() (Stmt, error) {
+	name, err := p.consume(IDENTIFIER, "Expect class name.")
+	if err != nil {
+		return nil, err
+	}
+
+	var superclass *VariableExpr
+	if p.match(LESS) {
+		superclassName, err := p.consume(IDENTIFIER, "Expect superclass name.")
+		if err != nil {
+			return nil, err
+		}
+		superclass = &VariableExpr{
+			Name: superclassName,
+		}
+	}
+
+	if _, err := p.consume(LEFT_BRACE, "Expect '{' before class body."); err != nil {
+		return nil, err
+	}
+
+	methods := []FunctionStmt{}
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		method, err := p.function("method")
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+
+	if _, err := p.consume(RIGHT_BRACE, "Expect '}' after class body."); err != nil {
+		return nil, err
+	}
+
+	return ClassStmt{
+		Name:       name,
+		Superclass: superclass,
+		Methods:    methods,
+	}, nil
+}
+
+// function parses the name, parameter list and body of a function.  kind
+// is used to tailor error messages.
+func (p *Parser) function(kind string) (FunctionStmt, error) {
+	name, err := p.consume(IDENTIFIER, "Expect "+kind+" name.")
+	if err != nil {
+		return FunctionStmt{}, err
+	}
+
+	if _, err := p.consume(LEFT_PAREN, "Expect '(' after "+kind+" name."); err != nil {
+		return FunctionStmt{}, err
+	}
+
+	params, body, err := p.functionBody(kind)
+	if err != nil {
+		return FunctionStmt{}, err
+	}
+
+	return FunctionStmt{
+		Name:   name,
+		Params: params,
+		Body:   body,
+	}, nil
+}
+
+// functionBody parses the parameter list and body of a function after
+// its opening '('.
+func (p *Parser) functionBody(kind string) ([]Param, []Stmt, error) {
+	params := []Param{}
+	if !p.check(RIGHT_PAREN) {
+		for {
+			if len(params) >= maxArgs {
+				// report without unwinding, the parser is not confused
+				p.error(p.peek(), fmt.Sprintf("Can't have more than %d parameters.", maxArgs))
+			}
+
+			rest := p.match(ELLIPSIS)
+			name, err := p.consume(IDENTIFIER, "Expect parameter name.")
+			if err != nil {
+				return nil, nil, err
+			}
+
+			param := Param{Name: name, Rest: rest}
+			if p.match(EQUAL) {
+				if rest {
+					p.error(name, "Rest parameter can't have a default value.")
+				}
+				param.Default, err = p.expression()
+				if err != nil {
+					return nil, nil, err
+				}
+			} else if !rest && len(params) > 0 && params[len(params)-1].Default != nil {
+				p.error(name, "Parameter without a default value can't follow one with a default value.")
+			}
+			params = append(params, param)
+
+			if !p.match(COMMA) {
+				break
+			}
+			if rest {
+				p.error(p.previous(), "Rest parameter must be the last parameter.")
+			}
+		}
+	}
+
+	if _, err := p.consume(RIGHT_PAREN, "Expect ')' after parameters."); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := p.consume(LEFT_BRACE, "Expect '{' before "+kind+" body."); err != nil {
+		return nil, nil, err
+	}
+
+	// loops do not extend into function bodies
+	enclosingLoops := p.loops
+	p.loops = nil
+	body, err := p.block()
+	p.loops = enclosingLoops
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return params, body, nil
+}
+
+// lambda parses an anonymous function expression after its 'fun'.
+func (p *Parser) lambda() (Expr, error) {
+	keyword := p.previous()
+	if _, err := p.consume(LEFT_PAREN, "Expect '(' after 'fun'."); err != nil {
+		return nil, err
+	}
+
+	params, body, err := p.functionBody("function")
+	if err != nil {
+		return nil, err
+	}
+
+	return FunctionExpr{
+		Keyword: keyword,
+		Params:  params,
+		Body:    body,
+	}, nil
+}
+
 func (p *Parser) varDeclaration() (Stmt, error) {
 	name, err := p.consume(IDENTIFIER, "Expect variable name")
 	if err != nil {
@@ -122,10 +284,46 @@ func (p *Parser) varDeclaration() (Stmt, error) {
 }
 
 func (p *Parser) statement() (Stmt, error) {
+	if p.check(IDENTIFIER) && p.checkNext(COLON) {
+		return p.labeledStatement()
+	}
+
+	if p.match(BREAK) {
+		return p.breakStatement()
+	}
+
+	if p.match(CONTINUE) {
+		return p.continueStatement()
+	}
+
+	if p.match(FOR) {
+		return p.forStatement("")
+	}
+
+	if p.match(IF) {
+		return p.ifStatement()
+	}
+
 	if p.match(PRINT) {
 		return p.printStatement()
 	}
 
+	if p.match(RETURN) {
+		return p.returnStatement()
+	}
+
+	if p.match(THROW) {
+		return p.throwStatement()
+	}
+
+	if p.match(TRY) {
+		return p.tryStatement()
+	}
+
+	if p.match(WHILE) {
+		return p.whileStatement("")
+	}
+
 	if p.match(LEFT_BRACE) {
 		stmts, err := p.block()
 		return BlockStmt{
@@ -150,190 +348,1033 @@ func (p *Parser) block() ([]Stmt, error) {
 	return stmts, nil
 }
 
-func (p *Parser) printStatement() (Stmt, error) {
-	expr, err := p.expression()
+// labeledStatement parses a loop preceded by a label that break and
+// continue statements in its body can name.
+func (p *Parser) labeledStatement() (Stmt, error) {
+	label := p.advance()
+	p.advance() // consume the ':'
+
+	for _, l := range p.loops {
+		if l == label.Lexeme {
+			return nil, p.error(label, fmt.Sprintf("Label '%s' is already in use.", label.Lexeme))
+		}
+	}
+
+	if p.match(WHILE) {
+		return p.whileStatement(label.Lexeme)
+	}
+
+	if p.match(FOR) {
+		return p.forStatement(label.Lexeme)
+	}
+
+	return nil, p.error(p.peek(), "Expect loop after label.")
+}
+
+func (p *Parser) breakStatement() (Stmt, error) {
+	keyword := p.previous()
+	label, err := p.loopJumpLabel(keyword)
 	if err != nil {
 		return nil, err
 	}
 
-	p.consume(SEMICOLON, "Expect ';' after value.")
-	return PrintStmt{
-		Expr: expr,
+	return BreakStmt{
+		Keyword: keyword,
+		Label:   label,
 	}, nil
 }
 
-func (p *Parser) expressionStatement() (Stmt, error) {
-	expr, err := p.expression()
+func (p *Parser) continueStatement() (Stmt, error) {
+	keyword := p.previous()
+	label, err := p.loopJumpLabel(keyword)
 	if err != nil {
 		return nil, err
 	}
 
-	p.consume(SEMICOLON, "Expect ';' after expression.")
-	return ExprStmt{
-		Expr: expr,
+	return ContinueStmt{
+		Keyword: keyword,
+		Label:   label,
 	}, nil
 }
 
-func (p *Parser) expression() (Expr, error) {
-	return p.assignment()
+// loopJumpLabel parses the optional label and terminating semicolon of a
+// break or continue statement, reporting the statement if it does not
+// appear inside a loop with that label.
+func (p *Parser) loopJumpLabel(keyword Token) (*Token, error) {
+	var label *Token
+	if p.match(IDENTIFIER) {
+		name := p.previous()
+		label = &name
+	}
+
+	if _, err := p.consume(SEMICOLON, fmt.Sprintf("Expect ';' after '%s'.", keyword.Lexeme)); err != nil {
+		return nil, err
+	}
+
+	if len(p.loops) == 0 {
+		// report without unwinding, the parser is not confused
+		p.error(keyword, fmt.Sprintf("Can't use '%s' outside of a loop.", keyword.Lexeme))
+	} else if label != nil && !slices.Contains(p.loops, label.Lexeme) {
+		p.error(*label, fmt.Sprintf("No enclosing loop labeled '%s'.", label.Lexeme))
+	}
+
+	return label, nil
 }
 
-func (p *Parser) assignment() (Expr, error) {
-	expr, err := p.equality()
+// loopBody parses the body of a loop with the given label.
+func (p *Parser) loopBody(label string) (Stmt, error) {
+	p.loops = append(p.loops, label)
+	defer func() {
+		p.loops = p.loops[:len(p.loops)-1]
+	}()
+	return p.statement()
+}
+
+// forStatement parses a C-style for loop and desugars it into a while
+// loop.  The increment runs after the body on each iteration, including
+// those ended by continue, and the initializer, if any, is scoped to a
+// block wrapping the whole loop.
+func (p *Parser) forStatement(label string) (Stmt, error) {
+	if _, err := p.consume(LEFT_PAREN, "Expect '(' after 'for'."); err != nil {
+		return nil, err
+	}
+
+	var initializer Stmt
+	var err error
+	if p.match(SEMICOLON) {
+		initializer = nil
+	} else if p.match(VAR) {
+		initializer, err = p.varDeclaration()
+	} else {
+		initializer, err = p.expressionStatement()
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if p.match(EQUAL) {
-		equals := p.previous()
-		value, err := p.assignment()
+	var condition Expr
+	if !p.check(SEMICOLON) {
+		condition, err = p.expression()
 		if err != nil {
 			return nil, err
 		}
+	}
+	if _, err := p.consume(SEMICOLON, "Expect ';' after loop condition."); err != nil {
+		return nil, err
+	}
 
-		if varExpr, ok := expr.(VariableExpr); ok {
-			return AssignExpr{
-				Name:  varExpr.Name,
-				Value: value,
-			}, nil
+	var increment Expr
+	if !p.check(RIGHT_PAREN) {
+		increment, err = p.expression()
+		if err != nil {
+			return nil, err
 		}
-
-		Error(equals.Line, "Invalid assignment target.")
+	}
+	if _, err := p.consume(RIGHT_PAREN, "Expect ')' after for clauses."); err != nil {
+		return nil, err
 	}
 
-	return expr, nil
-}
-
-func (p *Parser) equality() (Expr, error) {
-	expr, err := p.comparison()
+	body, err := p.loopBody(label)
 	if err != nil {
 		return nil, err
 	}
 
-	for p.match(BANG_EQUAL, EQUAL_EQUAL) {
-		operator := p.previous()
-		right, err := p.comparison()
-		if err != nil {
-			return nil, err
+	if condition == nil {
+		condition = LiteralExpr{
+			Value: true,
 		}
-		expr = BinaryExpr{
-			Left:  expr,
-			Op:    operator,
-			Right: right,
+	}
+	body = WhileStmt{
+		Condition: condition,
+		Body:      body,
+		Increment: increment,
+		Label:     label,
+	}
+
+	if initializer != nil {
+		body = BlockStmt{
+			Stmts: []Stmt{
+				initializer,
+				body,
+			},
 		}
 	}
-	return expr, err
+
+	return body, nil
 }
 
-func (p *Parser) comparison() (Expr, error) {
-	expr, err := p.term()
+// ifStatement parses the remainder of an if statement.  The else branch
+// binds to the nearest preceding if, which falls out of eagerly looking
+// for an else after parsing the then branch.
+func (p *Parser) ifStatement() (Stmt, error) {
+	if _, err := p.consume(LEFT_PAREN, "Expect '(' after 'if'."); err != nil {
+		return nil, err
+	}
+
+	condition, err := p.expression()
 	if err != nil {
 		return nil, err
 	}
 
-	for p.match(GREATER, GREATER_EQUAL, LESS, LESS_EQUAL) {
-		operator := p.previous()
-		right, err := p.term()
-		if err != nil {
-			return nil, err
-		}
-		expr = BinaryExpr{
-			Op:    operator,
-			Left:  expr,
-			Right: right,
-		}
+	if _, err := p.consume(RIGHT_PAREN, "Expect ')' after if condition."); err != nil {
+		return nil, err
 	}
-	return expr, err
-}
 
-func (p *Parser) term() (Expr, error) {
-	expr, err := p.factor()
+	thenBranch, err := p.statement()
 	if err != nil {
 		return nil, err
 	}
 
-	for p.match(MINUS, PLUS) {
-		operator := p.previous()
-		right, err := p.factor()
+	var elseBranch Stmt
+	if p.match(ELSE) {
+		elseBranch, err = p.statement()
 		if err != nil {
 			return nil, err
 		}
-		expr = BinaryExpr{
-			Op:    operator,
-			Left:  expr,
-			Right: right,
-		}
 	}
 
-	return expr, err
+	return IfStmt{
+		Condition:  condition,
+		ThenBranch: thenBranch,
+		ElseBranch: elseBranch,
+	}, nil
 }
 
-func (p *Parser) factor() (Expr, error) {
-	expr, err := p.unary()
-	if err != nil {
-		return nil, err
-	}
+func (p *Parser) returnStatement() (Stmt, error) {
+	keyword := p.previous()
 
-	for p.match(SLASH, STAR) {
-		operator := p.previous()
-		right, err := p.unary()
+	var value Expr
+	if !p.check(SEMICOLON) {
+		var err error
+		value, err = p.expression()
 		if err != nil {
 			return nil, err
 		}
-		expr = BinaryExpr{
-			Op:    operator,
-			Left:  expr,
-			Right: right,
-		}
 	}
 
-	return expr, err
+	if _, err := p.consume(SEMICOLON, "Expect ';' after return value."); err != nil {
+		return nil, err
+	}
+
+	return ReturnStmt{
+		Keyword: keyword,
+		Value:   value,
+	}, nil
 }
 
-func (p *Parser) unary() (Expr, error) {
-	if p.match(BANG, MINUS) {
-		operator := p.previous()
-		right, err := p.unary()
-		return UnaryExpr{
-			Op:    operator,
-			Right: right,
-		}, err
+func (p *Parser) throwStatement() (Stmt, error) {
+	keyword := p.previous()
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
 	}
-	return p.primary()
-}
 
-func (p *Parser) primary() (Expr, error) {
-	if p.match(FALSE) {
-		return LiteralExpr{
-			Value: false,
-		}, nil
+	if _, err := p.consume(SEMICOLON, "Expect ';' after thrown value."); err != nil {
+		return nil, err
 	}
-	if p.match(TRUE) {
-		return LiteralExpr{
-			Value: true,
-		}, nil
+
+	return ThrowStmt{
+		Keyword: keyword,
+		Value:   value,
+	}, nil
+}
+
+// tryStatement parses a try block followed by a catch clause, a finally
+// clause or both.
+func (p *Parser) tryStatement() (Stmt, error) {
+	if _, err := p.consume(LEFT_BRACE, "Expect '{' after 'try'."); err != nil {
+		return nil, err
 	}
-	if p.match(NIL) {
-		return LiteralExpr{
-			Value: nil,
-		}, nil
+	body, err := p.block()
+	if err != nil {
+		return nil, err
 	}
 
-	if p.match(NUMBER, STRING) {
-		return LiteralExpr{
-			Value: p.previous().Literal,
-		}, nil
+	stmt := TryStmt{
+		Body: body,
 	}
 
-	if p.match(LEFT_PAREN) {
-		expr, err := p.expression()
+	if p.match(CATCH) {
+		if _, err := p.consume(LEFT_PAREN, "Expect '(' after 'catch'."); err != nil {
+			return nil, err
+		}
+		name, err := p.consume(IDENTIFIER, "Expect caught variable name.")
 		if err != nil {
 			return nil, err
 		}
-		_, err = p.consume(RIGHT_PAREN, "Expect ')' after expression.")
-		return GroupingExpr{
-			Expression: expr,
-		}, err
+		if _, err := p.consume(RIGHT_PAREN, "Expect ')' after caught variable name."); err != nil {
+			return nil, err
+		}
+		if _, err := p.consume(LEFT_BRACE, "Expect '{' before catch body."); err != nil {
+			return nil, err
+		}
+		catchBody, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+
+		stmt.Catch = &CatchClause{
+			Name: name,
+			Body: catchBody,
+		}
+	}
+
+	if p.match(FINALLY) {
+		if _, err := p.consume(LEFT_BRACE, "Expect '{' after 'finally'."); err != nil {
+			return nil, err
+		}
+		stmt.Finally, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.Catch == nil && stmt.Finally == nil {
+		return nil, p.error(p.peek(), "Expect 'catch' or 'finally' after try block.")
+	}
+
+	return stmt, nil
+}
+
+func (p *Parser) whileStatement(label string) (Stmt, error) {
+	if _, err := p.consume(LEFT_PAREN, "Expect '(' after 'while'."); err != nil {
+		return nil, err
+	}
+
+	condition, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.consume(RIGHT_PAREN, "Expect ')' after condition."); err != nil {
+		return nil, err
+	}
+
+	body, err := p.loopBody(label)
+	if err != nil {
+		return nil, err
+	}
+
+	return WhileStmt{
+		Condition: condition,
+		Body:      body,
+		Label:     label,
+	}, nil
+}
+
+func (p *Parser) printStatement() (Stmt, error) {
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	p.consume(SEMICOLON, "Expect ';' after value.")
+	return PrintStmt{
+		Expr: expr,
+	}, nil
+}
+
+func (p *Parser) expressionStatement() (Stmt, error) {
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	p.consume(SEMICOLON, "Expect ';' after expression.")
+	return ExprStmt{
+		Expr: expr,
+	}, nil
+}
+
+func (p *Parser) expression() (Expr, error) {
+	return p.assignment()
+}
+
+func (p *Parser) assignment() (Expr, error) {
+	expr, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(EQUAL) {
+		equals := p.previous()
+		value, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+
+		switch target := expr.(type) {
+		case VariableExpr:
+			return AssignExpr{
+				Name:  target.Name,
+				Value: value,
+			}, nil
+		case GetExpr:
+			return SetExpr{
+				Object: target.Object,
+				Name:   target.Name,
+				Value:  value,
+			}, nil
+		case IndexExpr:
+			return IndexSetExpr{
+				Object:  target.Object,
+				Bracket: target.Bracket,
+				Index:   target.Index,
+				Value:   value,
+			}, nil
+		}
+
+		Error(equals.Line, "Invalid assignment target.")
+	} else if p.match(PLUS_EQUAL, MINUS_EQUAL, STAR_EQUAL, SLASH_EQUAL) {
+		operator := p.previous()
+		value, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+
+		if !isAssignable(expr) {
+			Error(operator.Line, "Invalid assignment target.")
+			return expr, nil
+		}
+
+		return CompoundAssignExpr{
+			Target: expr,
+			Op:     binaryOperator(operator),
+			Value:  value,
+		}, nil
+	}
+
+	return expr, nil
+}
+
+// isAssignable reports whether expr can be the target of an assignment.
+func isAssignable(expr Expr) bool {
+	switch expr.(type) {
+	case VariableExpr, GetExpr, IndexExpr:
+		return true
+	}
+	return false
+}
+
+// binaryOperator returns the binary operator applied by a compound
+// assignment or increment operator, such as '+' for '+=' and '++'.
+func binaryOperator(operator Token) Token {
+	binary := Token{Line: operator.Line}
+	switch operator.Type {
+	case PLUS_EQUAL, PLUS_PLUS:
+		binary.Type, binary.Lexeme = PLUS, "+"
+	case MINUS_EQUAL, MINUS_MINUS:
+		binary.Type, binary.Lexeme = MINUS, "-"
+	case STAR_EQUAL:
+		binary.Type, binary.Lexeme = STAR, "*"
+	case SLASH_EQUAL:
+		binary.Type, binary.Lexeme = SLASH, "/"
+	}
+	return binary
+}
+
+// conditional parses cond ? then : else.  The else branch recurses into
+// conditional, making it right-associative so a ? b : c ? d : e is
+// a ? b : (c ? d : e).
+func (p *Parser) conditional() (Expr, error) {
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(QUESTION) {
+		thenBranch, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.consume(COLON, "Expect ':' after then branch of conditional expression.")
+		if err != nil {
+			return nil, err
+		}
+
+		elseBranch, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = ConditionalExpr{
+			Condition: expr,
+			Then:      thenBranch,
+			Else:      elseBranch,
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) or() (Expr, error) {
+	expr, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(OR) {
+		operator := p.previous()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		expr = LogicalExpr{
+			Op:    operator,
+			Left:  expr,
+			Right: right,
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) and() (Expr, error) {
+	expr, err := p.equality()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(AND) {
+		operator := p.previous()
+		right, err := p.equality()
+		if err != nil {
+			return nil, err
+		}
+		expr = LogicalExpr{
+			Op:    operator,
+			Left:  expr,
+			Right: right,
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) equality() (Expr, error) {
+	expr, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(BANG_EQUAL, EQUAL_EQUAL) {
+		operator := p.previous()
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		expr = BinaryExpr{
+			Left:  expr,
+			Op:    operator,
+			Right: right,
+		}
+	}
+	return expr, err
+}
+
+func (p *Parser) comparison() (Expr, error) {
+	expr, err := p.bitOr()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(GREATER, GREATER_EQUAL, LESS, LESS_EQUAL) {
+		operator := p.previous()
+		right, err := p.bitOr()
+		if err != nil {
+			return nil, err
+		}
+		expr = BinaryExpr{
+			Op:    operator,
+			Left:  expr,
+			Right: right,
+		}
+	}
+	return expr, err
+}
+
+func (p *Parser) bitOr() (Expr, error) {
+	return p.leftAssociative(p.bitXor, PIPE)
+}
+
+func (p *Parser) bitXor() (Expr, error) {
+	return p.leftAssociative(p.bitAnd, CARET)
+}
+
+func (p *Parser) bitAnd() (Expr, error) {
+	return p.leftAssociative(p.shift, AMPERSAND)
+}
+
+func (p *Parser) shift() (Expr, error) {
+	return p.leftAssociative(p.term, LESS_LESS, GREATER_GREATER)
+}
+
+// leftAssociative parses a chain of binary operators of one precedence
+// level, with operand parsing the next higher level.
+func (p *Parser) leftAssociative(operand func() (Expr, error), tokenTypes ...TokenType) (Expr, error) {
+	expr, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(tokenTypes...) {
+		operator := p.previous()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		expr = BinaryExpr{
+			Op:    operator,
+			Left:  expr,
+			Right: right,
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) term() (Expr, error) {
+	expr, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(MINUS, PLUS) {
+		operator := p.previous()
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		expr = BinaryExpr{
+			Op:    operator,
+			Left:  expr,
+			Right: right,
+		}
+	}
+
+	return expr, err
+}
+
+func (p *Parser) factor() (Expr, error) {
+	expr, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(SLASH, STAR, PERCENT, TILDE_SLASH) {
+		operator := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		expr = BinaryExpr{
+			Op:    operator,
+			Left:  expr,
+			Right: right,
+		}
+	}
+
+	return expr, err
+}
+
+func (p *Parser) unary() (Expr, error) {
+	if p.match(BANG, MINUS, TILDE) {
+		operator := p.previous()
+		right, err := p.unary()
+		return UnaryExpr{
+			Op:    operator,
+			Right: right,
+		}, err
+	}
+	return p.power()
+}
+
+// power binds tighter than a unary operator on its left but not on its
+// right, so -2 ** 2 is -(2 ** 2) and 2 ** -1 is allowed.  Recursing into
+// unary makes it right-associative.
+func (p *Parser) power() (Expr, error) {
+	expr, err := p.update()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(STAR_STAR) {
+		operator := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		expr = BinaryExpr{
+			Op:    operator,
+			Left:  expr,
+			Right: right,
+		}
+	}
+
+	return expr, nil
+}
+
+// update parses prefix and postfix increments and decrements, whose
+// operand has to be assignable.
+func (p *Parser) update() (Expr, error) {
+	if p.match(PLUS_PLUS, MINUS_MINUS) {
+		operator := p.previous()
+		target, err := p.call()
+		if err != nil {
+			return nil, err
+		}
+
+		if !isAssignable(target) {
+			Error(operator.Line, "Invalid increment target.")
+			return target, nil
+		}
+
+		return IncrementExpr{
+			Target: target,
+			Op:     binaryOperator(operator),
+			Prefix: true,
+		}, nil
+	}
+
+	expr, err := p.call()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(PLUS_PLUS, MINUS_MINUS) {
+		operator := p.previous()
+		if !isAssignable(expr) {
+			Error(operator.Line, "Invalid increment target.")
+			return expr, nil
+		}
+
+		return IncrementExpr{
+			Target: expr,
+			Op:     binaryOperator(operator),
+		}, nil
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) call() (Expr, error) {
+	expr, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if p.match(LEFT_PAREN) {
+			expr, err = p.finishCall(expr)
+			if err != nil {
+				return nil, err
+			}
+		} else if p.match(DOT) {
+			name, err := p.consume(IDENTIFIER, "Expect property name after '.'.")
+			if err != nil {
+				return nil, err
+			}
+			expr = GetExpr{
+				Object: expr,
+				Name:   name,
+			}
+		} else if p.match(LEFT_BRACKET) {
+			expr, err = p.finishIndex(expr)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			break
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) finishCall(callee Expr) (Expr, error) {
+	args := []Expr{}
+	var namedArgs []NamedArg
+	if !p.check(RIGHT_PAREN) {
+		for {
+			if len(args)+len(namedArgs) >= maxArgs {
+				// report without unwinding, the parser is not confused
+				p.error(p.peek(), fmt.Sprintf("Can't have more than %d arguments.", maxArgs))
+			}
+
+			if p.check(IDENTIFIER) && p.checkNext(COLON) {
+				name := p.advance()
+				p.advance()
+
+				value, err := p.expression()
+				if err != nil {
+					return nil, err
+				}
+
+				for _, arg := range namedArgs {
+					if arg.Name.Lexeme == name.Lexeme {
+						p.error(name, "Duplicate argument for parameter '"+name.Lexeme+"'.")
+					}
+				}
+				namedArgs = append(namedArgs, NamedArg{Name: name, Value: value})
+			} else {
+				if len(namedArgs) > 0 {
+					p.error(p.peek(), "Positional argument can't follow a named argument.")
+				}
+
+				var arg Expr
+				var err error
+				if p.match(ELLIPSIS) {
+					ellipsis := p.previous()
+					arg, err = p.expression()
+					arg = SpreadExpr{Ellipsis: ellipsis, Expr: arg}
+				} else {
+					arg, err = p.expression()
+				}
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+			}
+
+			if !p.match(COMMA) {
+				break
+			}
+		}
+	}
+
+	paren, err := p.consume(RIGHT_PAREN, "Expect ')' after arguments.")
+	if err != nil {
+		return nil, err
+	}
+
+	return CallExpr{
+		Callee:    callee,
+		Paren:     paren,
+		Args:      args,
+		NamedArgs: namedArgs,
+	}, nil
+}
+
+// finishIndex parses the remainder of an index or slice expression after
+// the opening '['.
+func (p *Parser) finishIndex(object Expr) (Expr, error) {
+	var start Expr
+	if !p.check(COLON) {
+		var err error
+		start, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !p.match(COLON) {
+		bracket, err := p.consume(RIGHT_BRACKET, "Expect ']' after index.")
+		if err != nil {
+			return nil, err
+		}
+		return IndexExpr{
+			Object:  object,
+			Bracket: bracket,
+			Index:   start,
+		}, nil
+	}
+
+	var end Expr
+	if !p.check(RIGHT_BRACKET) {
+		var err error
+		end, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bracket, err := p.consume(RIGHT_BRACKET, "Expect ']' after slice.")
+	if err != nil {
+		return nil, err
+	}
+
+	return SliceExpr{
+		Object:  object,
+		Bracket: bracket,
+		Start:   start,
+		End:     end,
+	}, nil
+}
+
+func (p *Parser) list() (Expr, error) {
+	elements := []Expr{}
+	for !p.check(RIGHT_BRACKET) && !p.isAtEnd() {
+		element, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+
+		if !p.match(COMMA) {
+			break
+		}
+	}
+
+	bracket, err := p.consume(RIGHT_BRACKET, "Expect ']' after list elements.")
+	if err != nil {
+		return nil, err
+	}
+
+	return ListExpr{
+		Bracket:  bracket,
+		Elements: elements,
+	}, nil
+}
+
+// mapLiteral parses the entries of a map after the opening '{'.  Braces
+// only start a map in expression position, at the start of a statement
+// they always start a block.
+func (p *Parser) mapLiteral() (Expr, error) {
+	keys := []Expr{}
+	colons := []Token{}
+	values := []Expr{}
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		key, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		colon, err := p.consume(COLON, "Expect ':' after map key.")
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+		colons = append(colons, colon)
+		values = append(values, value)
+
+		if !p.match(COMMA) {
+			break
+		}
+	}
+
+	brace, err := p.consume(RIGHT_BRACE, "Expect '}' after map entries.")
+	if err != nil {
+		return nil, err
+	}
+
+	return MapExpr{
+		Brace:  brace,
+		Keys:   keys,
+		Colons: colons,
+		Values: values,
+	}, nil
+}
+
+// interpolation parses the embedded expressions and literal pieces of an
+// interpolated string after its first INTERPOLATION token.  Empty
+// literal pieces are dropped.
+func (p *Parser) interpolation() (Expr, error) {
+	parts := []Expr{}
+	piece := p.previous()
+	for {
+		if text, _ := piece.Literal.(string); text != "" {
+			parts = append(parts, LiteralExpr{
+				Value: text,
+			})
+		}
+
+		if piece.Type == STRING {
+			break
+		}
+
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, expr)
+
+		if !p.match(INTERPOLATION, STRING) {
+			return nil, p.error(p.peek(), "Expect '}' after interpolated expression.")
+		}
+		piece = p.previous()
+	}
+
+	return InterpolationExpr{
+		Parts: parts,
+	}, nil
+}
+
+func (p *Parser) primary() (Expr, error) {
+	if p.match(FALSE) {
+		return LiteralExpr{
+			Value: false,
+		}, nil
+	}
+	if p.match(TRUE) {
+		return LiteralExpr{
+			Value: true,
+		}, nil
+	}
+	if p.match(NIL) {
+		return LiteralExpr{
+			Value: nil,
+		}, nil
+	}
+
+	if p.match(NUMBER, STRING) {
+		return LiteralExpr{
+			Value: p.previous().Literal,
+		}, nil
+	}
+
+	if p.match(INTERPOLATION) {
+		return p.interpolation()
+	}
+
+	if p.match(LEFT_PAREN) {
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(RIGHT_PAREN, "Expect ')' after expression.")
+		return GroupingExpr{
+			Expression: expr,
+		}, err
+	}
+
+	if p.match(LEFT_BRACKET) {
+		return p.list()
+	}
+
+	if p.match(LEFT_BRACE) {
+		return p.mapLiteral()
+	}
+
+	if p.match(FUN) {
+		return p.lambda()
+	}
+
+	if p.match(SUPER) {
+		keyword := p.previous()
+		if _, err := p.consume(DOT, "Expect '.' after 'super'."); err != nil {
+			return nil, err
+		}
+		method, err := p.consume(IDENTIFIER, "Expect superclass method name.")
+		if err != nil {
+			return nil, err
+		}
+		return SuperExpr{
+			Keyword: keyword,
+			Method:  method,
+		}, nil
+	}
+
+	if p.match(THIS) {
+		return ThisExpr{
+			Keyword: p.previous(),
+		}, nil
 	}
 
 	if p.match(IDENTIFIER) {
@@ -354,7 +1395,7 @@ func (p *Parser) Synchronize() {
 		}
 
 		switch p.peek().Type {
-		case CLASS, FUN, VAR, FOR, IF, WHILE, PRINT, RETURN:
+		case CLASS, FUN, VAR, FOR, IF, WHILE, PRINT, RETURN, THROW, TRY, BREAK, CONTINUE:
 			return
 		}
 
@@ -644,3 +644,1171 @@ func TestEquality(t *testing.T) {
 		})
 	}
 }
+
+func parseSource(t *testing.T, source string) []Stmt {
+	t.Helper()
+	hadError = false
+	tokens, err := NewScanner(source).scanTokens()
+	require.NoError(t, err)
+	p, err := NewParser(tokens)
+	require.NoError(t, err)
+	stmts, err := p.Parse()
+	require.NoError(t, err)
+	require.False(t, hadError)
+	return stmts
+}
+
+func TestIfStatement(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected Stmt
+	}{
+		{
+			name:   "no_else",
+			source: "if (true) print 1;",
+			expected: IfStmt{
+				Condition: LiteralExpr{
+					Value: true,
+				},
+				ThenBranch: PrintStmt{
+					Expr: LiteralExpr{
+						Value: int64(1),
+					},
+				},
+			},
+		},
+		{
+			name:   "else",
+			source: "if (true) print 1; else print 2;",
+			expected: IfStmt{
+				Condition: LiteralExpr{
+					Value: true,
+				},
+				ThenBranch: PrintStmt{
+					Expr: LiteralExpr{
+						Value: int64(1),
+					},
+				},
+				ElseBranch: PrintStmt{
+					Expr: LiteralExpr{
+						Value: int64(2),
+					},
+				},
+			},
+		},
+		{
+			name:   "dangling_else",
+			source: "if (true) if (false) print 1; else print 2;",
+			expected: IfStmt{
+				Condition: LiteralExpr{
+					Value: true,
+				},
+				ThenBranch: IfStmt{
+					Condition: LiteralExpr{
+						Value: false,
+					},
+					ThenBranch: PrintStmt{
+						Expr: LiteralExpr{
+							Value: int64(1),
+						},
+					},
+					ElseBranch: PrintStmt{
+						Expr: LiteralExpr{
+							Value: int64(2),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tt.expected, stmts[0])
+		})
+	}
+}
+
+func TestWhileStatement(t *testing.T) {
+	stmts := parseSource(t, "while (true) print 1;")
+	require.Len(t, stmts, 1)
+	assert.Equal(t, WhileStmt{
+		Condition: LiteralExpr{
+			Value: true,
+		},
+		Body: PrintStmt{
+			Expr: LiteralExpr{
+				Value: int64(1),
+			},
+		},
+	}, stmts[0])
+}
+
+func TestForStatement(t *testing.T) {
+	i := Token{Type: IDENTIFIER, Lexeme: "i", Line: 1}
+	initializer := VarStmt{
+		Name: i,
+		Expr: LiteralExpr{
+			Value: int64(0),
+		},
+	}
+	condition := BinaryExpr{
+		Op: Token{
+			Type:   LESS,
+			Lexeme: "<",
+			Line:   1,
+		},
+		Left: VariableExpr{
+			Name: i,
+		},
+		Right: LiteralExpr{
+			Value: int64(3),
+		},
+	}
+	increment := AssignExpr{
+		Name: i,
+		Value: BinaryExpr{
+			Op: Token{
+				Type:   PLUS,
+				Lexeme: "+",
+				Line:   1,
+			},
+			Left: VariableExpr{
+				Name: i,
+			},
+			Right: LiteralExpr{
+				Value: int64(1),
+			},
+		},
+	}
+	body := PrintStmt{
+		Expr: VariableExpr{
+			Name: i,
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		source   string
+		expected Stmt
+	}{
+		{
+			name:   "all_clauses",
+			source: "for (var i = 0; i < 3; i = i + 1) print i;",
+			expected: BlockStmt{
+				Stmts: []Stmt{
+					initializer,
+					WhileStmt{
+						Condition: condition,
+						Body:      body,
+						Increment: increment,
+					},
+				},
+			},
+		},
+		{
+			name:   "expression_initializer",
+			source: "for (i = 0; i < 3; i = i + 1) print i;",
+			expected: BlockStmt{
+				Stmts: []Stmt{
+					ExprStmt{
+						Expr: AssignExpr{
+							Name: i,
+							Value: LiteralExpr{
+								Value: int64(0),
+							},
+						},
+					},
+					WhileStmt{
+						Condition: condition,
+						Body:      body,
+						Increment: increment,
+					},
+				},
+			},
+		},
+		{
+			name:   "no_initializer",
+			source: "for (; i < 3; i = i + 1) print i;",
+			expected: WhileStmt{
+				Condition: condition,
+				Body:      body,
+				Increment: increment,
+			},
+		},
+		{
+			name:   "no_condition",
+			source: "for (var i = 0;; i = i + 1) print i;",
+			expected: BlockStmt{
+				Stmts: []Stmt{
+					initializer,
+					WhileStmt{
+						Condition: LiteralExpr{
+							Value: true,
+						},
+						Body:      body,
+						Increment: increment,
+					},
+				},
+			},
+		},
+		{
+			name:   "no_increment",
+			source: "for (var i = 0; i < 3;) print i;",
+			expected: BlockStmt{
+				Stmts: []Stmt{
+					initializer,
+					WhileStmt{
+						Condition: condition,
+						Body:      body,
+					},
+				},
+			},
+		},
+		{
+			name:   "no_clauses",
+			source: "for (;;) print i;",
+			expected: WhileStmt{
+				Condition: LiteralExpr{
+					Value: true,
+				},
+				Body: body,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tt.expected, stmts[0])
+		})
+	}
+}
+
+func TestLogical(t *testing.T) {
+	a := VariableExpr{Name: Token{Type: IDENTIFIER, Lexeme: "a", Line: 1}}
+	b := VariableExpr{Name: Token{Type: IDENTIFIER, Lexeme: "b", Line: 1}}
+	c := VariableExpr{Name: Token{Type: IDENTIFIER, Lexeme: "c", Line: 1}}
+	or := Token{Type: OR, Lexeme: "or", Line: 1}
+	and := Token{Type: AND, Lexeme: "and", Line: 1}
+
+	testCases := []struct {
+		name     string
+		source   string
+		expected Expr
+	}{
+		{
+			name:   "or",
+			source: "a or b;",
+			expected: LogicalExpr{
+				Op:    or,
+				Left:  a,
+				Right: b,
+			},
+		},
+		{
+			name:   "and_binds_tighter",
+			source: "a or b and c;",
+			expected: LogicalExpr{
+				Op:   or,
+				Left: a,
+				Right: LogicalExpr{
+					Op:    and,
+					Left:  b,
+					Right: c,
+				},
+			},
+		},
+		{
+			name:   "associativity",
+			source: "a and b and c;",
+			expected: LogicalExpr{
+				Op: and,
+				Left: LogicalExpr{
+					Op:    and,
+					Left:  a,
+					Right: b,
+				},
+				Right: c,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			expr, ok := stmts[0].(ExprStmt)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, expr.Expr)
+		})
+	}
+}
+
+func TestOperatorPrecedence(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{name: "modulo_with_factor", source: "a * b % c;", expected: "(% (* a b) c)"},
+		{name: "integer_division_with_factor", source: "a ~/ b * c - d;", expected: "(- (* (~/ a b) c) d)"},
+		{name: "integer_division_of_bitwise_not", source: "a ~/ ~b;", expected: "(~/ a (~ b))"},
+		{name: "power_over_factor", source: "a * b ** c;", expected: "(* a (** b c))"},
+		{name: "power_right_associative", source: "a ** b ** c;", expected: "(** a (** b c))"},
+		{name: "power_over_unary", source: "-a ** b;", expected: "(- (** a b))"},
+		{name: "power_unary_exponent", source: "a ** -b;", expected: "(** a (- b))"},
+		{name: "power_of_call", source: "f() ** a[0];", expected: "(** (call f) (index a 0))"},
+		{name: "bitwise_not", source: "~a & b;", expected: "(& (~ a) b)"},
+		{name: "shift_below_term", source: "a << b + c;", expected: "(<< a (+ b c))"},
+		{name: "and_over_xor_over_or", source: "a | b ^ c & d;", expected: "(| a (^ b (& c d)))"},
+		{name: "shift_over_and", source: "a & b >> c;", expected: "(& a (>> b c))"},
+		{name: "or_over_comparison", source: "a | b == c;", expected: "(== (| a b) c)"},
+		{name: "left_associative", source: "a >> b << c;", expected: "(<< (>> a b) c)"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			expr, ok := stmts[0].(ExprStmt)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, expr.Expr.Print())
+		})
+	}
+}
+
+func TestConditional(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{name: "conditional", source: "a ? b : c;", expected: "(?: a b c)"},
+		{name: "right_associative", source: "a ? b : c ? d : e;", expected: "(?: a b (?: c d e))"},
+		{name: "nested_then", source: "a ? b ? c : d : e;", expected: "(?: a (?: b c d) e)"},
+		{name: "below_or", source: "a or b ? c : d;", expected: "(?: (or a b) c d)"},
+		{name: "assignment_in_then", source: "a ? b = c : d;", expected: "(?: a (= b c) d)"},
+		{name: "assignment_of_conditional", source: "x = a ? b : c;", expected: "(= x (?: a b c))"},
+		{name: "slice_bound", source: "l[a ? 1 : 2:];", expected: "(slice l (?: a 1 2) nil)"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			expr, ok := stmts[0].(ExprStmt)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, expr.Expr.Print())
+		})
+	}
+}
+
+func TestConditionalErrors(t *testing.T) {
+	testCases := []string{
+		"a ? b;",
+		"a ? b c;",
+		"a ? : c;",
+		"a ? b : ;",
+	}
+
+	for _, source := range testCases {
+		t.Run(source, func(t *testing.T) {
+			hadError = false
+			tokens, err := NewScanner(source).scanTokens()
+			require.NoError(t, err)
+			p, err := NewParser(tokens)
+			require.NoError(t, err)
+			_, err = p.Parse()
+			require.NoError(t, err)
+			assert.True(t, hadError)
+			hadError = false
+		})
+	}
+}
+
+func TestCompoundAssignment(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{name: "plus_equal", source: "a += 1;", expected: "(+= a 1)"},
+		{name: "minus_equal", source: "a -= 1;", expected: "(-= a 1)"},
+		{name: "star_equal", source: "a *= 1;", expected: "(*= a 1)"},
+		{name: "slash_equal", source: "a /= 1;", expected: "(/= a 1)"},
+		{name: "right_associative", source: "a += b -= 1;", expected: "(+= a (-= b 1))"},
+		{name: "field", source: "a.b *= 2;", expected: "(*= (. b a) 2)"},
+		{name: "index", source: "a[0] /= 2;", expected: "(/= (index a 0) 2)"},
+		{name: "prefix_increment", source: "++a;", expected: "(pre++ a)"},
+		{name: "prefix_decrement", source: "--a.b;", expected: "(pre-- (. b a))"},
+		{name: "postfix_increment", source: "a[0]++;", expected: "(post++ (index a 0))"},
+		{name: "postfix_decrement", source: "a--;", expected: "(post-- a)"},
+		{name: "postfix_in_binary", source: "a++ + b;", expected: "(+ (post++ a) b)"},
+		{name: "negated_increment", source: "-++a;", expected: "(- (pre++ a))"},
+		{name: "increment_power", source: "a++ ** 2;", expected: "(** (post++ a) 2)"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			expr, ok := stmts[0].(ExprStmt)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, expr.Expr.Print())
+		})
+	}
+}
+
+func TestInvalidCompoundAssignmentTargets(t *testing.T) {
+	testCases := []string{
+		"1 += 2;",
+		"a + b -= 2;",
+		"f() *= 2;",
+		"++1;",
+		"(a)++;",
+		"f()--;",
+		"++a++;",
+	}
+
+	for _, source := range testCases {
+		t.Run(source, func(t *testing.T) {
+			hadError = false
+			tokens, err := NewScanner(source).scanTokens()
+			require.NoError(t, err)
+			p, err := NewParser(tokens)
+			require.NoError(t, err)
+			_, err = p.Parse()
+			require.NoError(t, err)
+			assert.True(t, hadError)
+			hadError = false
+		})
+	}
+}
+
+func TestFunctionDeclaration(t *testing.T) {
+	stmts := parseSource(t, "fun add(a, b) { return a + b; }")
+	require.Len(t, stmts, 1)
+
+	a := Token{Type: IDENTIFIER, Lexeme: "a", Line: 1}
+	b := Token{Type: IDENTIFIER, Lexeme: "b", Line: 1}
+	assert.Equal(t, FunctionStmt{
+		Name:   Token{Type: IDENTIFIER, Lexeme: "add", Line: 1},
+		Params: []Param{{Name: a}, {Name: b}},
+		Body: []Stmt{
+			ReturnStmt{
+				Keyword: Token{Type: RETURN, Lexeme: "return", Line: 1},
+				Value: BinaryExpr{
+					Op:    Token{Type: PLUS, Lexeme: "+", Line: 1},
+					Left:  VariableExpr{Name: a},
+					Right: VariableExpr{Name: b},
+				},
+			},
+		},
+	}, stmts[0])
+}
+
+func TestCall(t *testing.T) {
+	f := VariableExpr{Name: Token{Type: IDENTIFIER, Lexeme: "f", Line: 1}}
+	paren := Token{Type: RIGHT_PAREN, Lexeme: ")", Line: 1}
+
+	testCases := []struct {
+		name     string
+		source   string
+		expected Expr
+	}{
+		{
+			name:   "no_args",
+			source: "f();",
+			expected: CallExpr{
+				Callee: f,
+				Paren:  paren,
+				Args:   []Expr{},
+			},
+		},
+		{
+			name:   "args",
+			source: "f(1, 2);",
+			expected: CallExpr{
+				Callee: f,
+				Paren:  paren,
+				Args: []Expr{
+					LiteralExpr{Value: int64(1)},
+					LiteralExpr{Value: int64(2)},
+				},
+			},
+		},
+		{
+			name:   "curried",
+			source: "f(1)(2);",
+			expected: CallExpr{
+				Callee: CallExpr{
+					Callee: f,
+					Paren:  paren,
+					Args: []Expr{
+						LiteralExpr{Value: int64(1)},
+					},
+				},
+				Paren: paren,
+				Args: []Expr{
+					LiteralExpr{Value: int64(2)},
+				},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			expr, ok := stmts[0].(ExprStmt)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, expr.Expr)
+		})
+	}
+}
+
+func TestTooManyArguments(t *testing.T) {
+	source := "f(0"
+	for i := 1; i <= maxArgs; i++ {
+		source += ", 0"
+	}
+	source += ");"
+
+	hadError = false
+	tokens, err := NewScanner(source).scanTokens()
+	require.NoError(t, err)
+	p, err := NewParser(tokens)
+	require.NoError(t, err)
+	_, err = p.Parse()
+	require.NoError(t, err)
+	assert.True(t, hadError)
+	hadError = false
+}
+
+func TestLambda(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{name: "no_params", source: "var f = fun () {};", expected: "(fun ())"},
+		{name: "params", source: "var f = fun (a, b) { return a + b; };", expected: "(fun (a b))"},
+		{name: "argument", source: "var r = map(l, fun (x) { return x * 2; });", expected: "(call map l (fun (x)))"},
+		{name: "immediately_invoked", source: "var r = fun (x) { return x; }(1);", expected: "(call (fun (x)) 1)"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			stmt, ok := stmts[0].(VarStmt)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, stmt.Expr.Print())
+		})
+	}
+}
+
+func TestLambdaAtStatementStart(t *testing.T) {
+	stmts := parseSource(t, "fun f() {} fun () {};")
+	require.Len(t, stmts, 2)
+
+	_, ok := stmts[0].(FunctionStmt)
+	assert.True(t, ok)
+
+	expr, ok := stmts[1].(ExprStmt)
+	require.True(t, ok)
+	_, ok = expr.Expr.(FunctionExpr)
+	assert.True(t, ok)
+}
+
+func TestDefaultsAndNamedArguments(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{name: "defaults", source: "var f = fun (a, b = 1, c = a + b) {};", expected: "(fun (a b=1 c=(+ a b)))"},
+		{name: "named", source: "var r = f(1, b: 2, c: x);", expected: "(call f 1 (b: 2) (c: x))"},
+		{name: "named_only", source: "var r = f(b: 2);", expected: "(call f (b: 2))"},
+		{name: "conditional_argument", source: "var r = f(a ? b : c);", expected: "(call f (?: a b c))"},
+		{name: "rest", source: "var f = fun (a, b = 1, ...rest) {};", expected: "(fun (a b=1 ...rest))"},
+		{name: "only_rest", source: "var f = fun (...rest) {};", expected: "(fun (...rest))"},
+		{name: "spread", source: "var r = f(1, ...l, ...g(), x: 2);", expected: "(call f 1 (... l) (... (call g)) (x: 2))"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			stmt, ok := stmts[0].(VarStmt)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, stmt.Expr.Print())
+		})
+	}
+}
+
+func TestDefaultsAndNamedArgumentErrors(t *testing.T) {
+	testCases := []string{
+		"fun f(a = 1, b) {}",
+		"fun f(a = ) {}",
+		"f(a: 1, 2);",
+		"f(a: 1, a: 2);",
+		"f(a: 1, ...l);",
+		"f(...);",
+		"fun f(...a, b) {}",
+		"fun f(...a = []) {}",
+		"fun f(...) {}",
+		"f(a: );",
+	}
+
+	for _, source := range testCases {
+		t.Run(source, func(t *testing.T) {
+			hadError = false
+			tokens, err := NewScanner(source).scanTokens()
+			require.NoError(t, err)
+			p, err := NewParser(tokens)
+			require.NoError(t, err)
+			_, err = p.Parse()
+			require.NoError(t, err)
+			assert.True(t, hadError)
+			hadError = false
+		})
+	}
+}
+
+func TestClassDeclaration(t *testing.T) {
+	stmts := parseSource(t, "class A { init(x) { this.x = x; } get() { return this.x; } }")
+	require.Len(t, stmts, 1)
+
+	this := ThisExpr{Keyword: Token{Type: THIS, Lexeme: "this", Line: 1}}
+	x := Token{Type: IDENTIFIER, Lexeme: "x", Line: 1}
+	assert.Equal(t, ClassStmt{
+		Name: Token{Type: IDENTIFIER, Lexeme: "A", Line: 1},
+		Methods: []FunctionStmt{
+			{
+				Name:   Token{Type: IDENTIFIER, Lexeme: "init", Line: 1},
+				Params: []Param{{Name: x}},
+				Body: []Stmt{
+					ExprStmt{
+						Expr: SetExpr{
+							Object: this,
+							Name:   x,
+							Value:  VariableExpr{Name: x},
+						},
+					},
+				},
+			},
+			{
+				Name:   Token{Type: IDENTIFIER, Lexeme: "get", Line: 1},
+				Params: []Param{},
+				Body: []Stmt{
+					ReturnStmt{
+						Keyword: Token{Type: RETURN, Lexeme: "return", Line: 1},
+						Value: GetExpr{
+							Object: this,
+							Name:   x,
+						},
+					},
+				},
+			},
+		},
+	}, stmts[0])
+}
+
+func TestPropertyAccess(t *testing.T) {
+	a := VariableExpr{Name: Token{Type: IDENTIFIER, Lexeme: "a", Line: 1}}
+	b := Token{Type: IDENTIFIER, Lexeme: "b", Line: 1}
+	c := Token{Type: IDENTIFIER, Lexeme: "c", Line: 1}
+
+	testCases := []struct {
+		name     string
+		source   string
+		expected Expr
+	}{
+		{
+			name:   "get",
+			source: "a.b.c;",
+			expected: GetExpr{
+				Object: GetExpr{
+					Object: a,
+					Name:   b,
+				},
+				Name: c,
+			},
+		},
+		{
+			name:   "set",
+			source: "a.b.c = 1;",
+			expected: SetExpr{
+				Object: GetExpr{
+					Object: a,
+					Name:   b,
+				},
+				Name:  c,
+				Value: LiteralExpr{Value: int64(1)},
+			},
+		},
+		{
+			name:   "method_call",
+			source: "a.b();",
+			expected: CallExpr{
+				Callee: GetExpr{
+					Object: a,
+					Name:   b,
+				},
+				Paren: Token{Type: RIGHT_PAREN, Lexeme: ")", Line: 1},
+				Args:  []Expr{},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			expr, ok := stmts[0].(ExprStmt)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, expr.Expr)
+		})
+	}
+}
+
+func TestSubclassDeclaration(t *testing.T) {
+	stmts := parseSource(t, "class B < A { m() { return super.m; } }")
+	require.Len(t, stmts, 1)
+
+	assert.Equal(t, ClassStmt{
+		Name: Token{Type: IDENTIFIER, Lexeme: "B", Line: 1},
+		Superclass: &VariableExpr{
+			Name: Token{Type: IDENTIFIER, Lexeme: "A", Line: 1},
+		},
+		Methods: []FunctionStmt{
+			{
+				Name:   Token{Type: IDENTIFIER, Lexeme: "m", Line: 1},
+				Params: []Param{},
+				Body: []Stmt{
+					ReturnStmt{
+						Keyword: Token{Type: RETURN, Lexeme: "return", Line: 1},
+						Value: SuperExpr{
+							Keyword: Token{Type: SUPER, Lexeme: "super", Line: 1},
+							Method:  Token{Type: IDENTIFIER, Lexeme: "m", Line: 1},
+						},
+					},
+				},
+			},
+		},
+	}, stmts[0])
+}
+
+func TestLoopJumps(t *testing.T) {
+	outer := Token{Type: IDENTIFIER, Lexeme: "outer", Line: 1}
+
+	testCases := []struct {
+		name     string
+		source   string
+		expected Stmt
+	}{
+		{
+			name:   "break",
+			source: "while (true) break;",
+			expected: WhileStmt{
+				Condition: LiteralExpr{Value: true},
+				Body: BreakStmt{
+					Keyword: Token{Type: BREAK, Lexeme: "break", Line: 1},
+				},
+			},
+		},
+		{
+			name:   "labeled_continue",
+			source: "outer: while (true) continue outer;",
+			expected: WhileStmt{
+				Condition: LiteralExpr{Value: true},
+				Body: ContinueStmt{
+					Keyword: Token{Type: CONTINUE, Lexeme: "continue", Line: 1},
+					Label:   &outer,
+				},
+				Label: "outer",
+			},
+		},
+		{
+			name:   "labeled_for",
+			source: "outer: for (;;) while (true) break outer;",
+			expected: WhileStmt{
+				Condition: LiteralExpr{Value: true},
+				Body: WhileStmt{
+					Condition: LiteralExpr{Value: true},
+					Body: BreakStmt{
+						Keyword: Token{Type: BREAK, Lexeme: "break", Line: 1},
+						Label:   &outer,
+					},
+				},
+				Label: "outer",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tt.expected, stmts[0])
+		})
+	}
+}
+
+func TestLoopJumpErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		source      string
+		expectError bool
+	}{
+		{
+			name:        "break_in_loop",
+			source:      "while (true) { if (true) break; }",
+			expectError: false,
+		},
+		{
+			name:        "break_outside_loop",
+			source:      "break;",
+			expectError: true,
+		},
+		{
+			name:        "continue_outside_loop",
+			source:      "{ continue; }",
+			expectError: true,
+		},
+		{
+			name:        "break_in_function_in_loop",
+			source:      "while (true) { fun f() { break; } }",
+			expectError: true,
+		},
+		{
+			name:        "loop_after_function_in_loop",
+			source:      "while (true) { fun f() {} break; }",
+			expectError: false,
+		},
+		{
+			name:        "undefined_label",
+			source:      "while (true) break outer;",
+			expectError: true,
+		},
+		{
+			name:        "label_out_of_scope",
+			source:      "outer: while (true) {} while (true) continue outer;",
+			expectError: true,
+		},
+		{
+			name:        "duplicate_label",
+			source:      "a: while (true) a: while (true) break a;",
+			expectError: true,
+		},
+		{
+			name:        "label_without_loop",
+			source:      "a: print 1;",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			hadError = false
+			tokens, err := NewScanner(tt.source).scanTokens()
+			require.NoError(t, err)
+			p, err := NewParser(tokens)
+			require.NoError(t, err)
+			_, err = p.Parse()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectError, hadError)
+			hadError = false
+		})
+	}
+}
+
+func TestListExpressions(t *testing.T) {
+	a := VariableExpr{Name: Token{Type: IDENTIFIER, Lexeme: "a", Line: 1}}
+	bracket := Token{Type: RIGHT_BRACKET, Lexeme: "]", Line: 1}
+
+	testCases := []struct {
+		name     string
+		source   string
+		expected Expr
+	}{
+		{
+			name:   "empty_list",
+			source: "[];",
+			expected: ListExpr{
+				Bracket:  bracket,
+				Elements: []Expr{},
+			},
+		},
+		{
+			name:   "list_trailing_comma",
+			source: "[1, 2,];",
+			expected: ListExpr{
+				Bracket: bracket,
+				Elements: []Expr{
+					LiteralExpr{Value: int64(1)},
+					LiteralExpr{Value: int64(2)},
+				},
+			},
+		},
+		{
+			name:   "index",
+			source: "a[0];",
+			expected: IndexExpr{
+				Object:  a,
+				Bracket: bracket,
+				Index:   LiteralExpr{Value: int64(0)},
+			},
+		},
+		{
+			name:   "index_assignment",
+			source: "a[0] = 1;",
+			expected: IndexSetExpr{
+				Object:  a,
+				Bracket: bracket,
+				Index:   LiteralExpr{Value: int64(0)},
+				Value:   LiteralExpr{Value: int64(1)},
+			},
+		},
+		{
+			name:   "slice",
+			source: "a[1:3];",
+			expected: SliceExpr{
+				Object:  a,
+				Bracket: bracket,
+				Start:   LiteralExpr{Value: int64(1)},
+				End:     LiteralExpr{Value: int64(3)},
+			},
+		},
+		{
+			name:   "slice_open",
+			source: "a[:];",
+			expected: SliceExpr{
+				Object:  a,
+				Bracket: bracket,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			expr, ok := stmts[0].(ExprStmt)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, expr.Expr)
+		})
+	}
+}
+
+func TestMapLiteral(t *testing.T) {
+	brace := Token{Type: RIGHT_BRACE, Lexeme: "}", Line: 1}
+	colon := Token{Type: COLON, Lexeme: ":", Line: 1}
+
+	testCases := []struct {
+		name     string
+		source   string
+		expected Stmt
+	}{
+		{
+			name:   "expression_position",
+			source: `var m = {"a": 1, 2: true,};`,
+			expected: VarStmt{
+				Name: Token{Type: IDENTIFIER, Lexeme: "m", Line: 1},
+				Expr: MapExpr{
+					Brace: brace,
+					Keys: []Expr{
+						LiteralExpr{Value: "a"},
+						LiteralExpr{Value: int64(2)},
+					},
+					Colons: []Token{colon, colon},
+					Values: []Expr{
+						LiteralExpr{Value: int64(1)},
+						LiteralExpr{Value: true},
+					},
+				},
+			},
+		},
+		{
+			name:   "empty_map",
+			source: `print {};`,
+			expected: PrintStmt{
+				Expr: MapExpr{
+					Brace:  brace,
+					Keys:   []Expr{},
+					Colons: []Token{},
+					Values: []Expr{},
+				},
+			},
+		},
+		{
+			name:   "statement_position_is_block",
+			source: `{}`,
+			expected: BlockStmt{
+				Stmts: []Stmt{},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tt.expected, stmts[0])
+		})
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	name := VariableExpr{Name: Token{Type: IDENTIFIER, Lexeme: "name", Line: 1}}
+
+	testCases := []struct {
+		name     string
+		source   string
+		expected Expr
+	}{
+		{
+			name:   "pieces",
+			source: `"Hello ${name}!";`,
+			expected: InterpolationExpr{
+				Parts: []Expr{
+					LiteralExpr{Value: "Hello "},
+					name,
+					LiteralExpr{Value: "!"},
+				},
+			},
+		},
+		{
+			name:   "only_expression",
+			source: `"${name}";`,
+			expected: InterpolationExpr{
+				Parts: []Expr{
+					name,
+				},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			expr, ok := stmts[0].(ExprStmt)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, expr.Expr)
+		})
+	}
+}
+
+func TestTryStatement(t *testing.T) {
+	e := Token{Type: IDENTIFIER, Lexeme: "e", Line: 1}
+	throw := ThrowStmt{
+		Keyword: Token{Type: THROW, Lexeme: "throw", Line: 1},
+		Value:   LiteralExpr{Value: "boom"},
+	}
+
+	testCases := []struct {
+		name     string
+		source   string
+		expected Stmt
+	}{
+		{
+			name:   "catch",
+			source: `try { throw "boom"; } catch (e) {}`,
+			expected: TryStmt{
+				Body:  []Stmt{throw},
+				Catch: &CatchClause{Name: e, Body: []Stmt{}},
+			},
+		},
+		{
+			name:   "finally",
+			source: `try { throw "boom"; } finally {}`,
+			expected: TryStmt{
+				Body:    []Stmt{throw},
+				Finally: []Stmt{},
+			},
+		},
+		{
+			name:   "catch_and_finally",
+			source: `try {} catch (e) { throw e; } finally { throw "boom"; }`,
+			expected: TryStmt{
+				Body: []Stmt{},
+				Catch: &CatchClause{Name: e, Body: []Stmt{
+					ThrowStmt{
+						Keyword: Token{Type: THROW, Lexeme: "throw", Line: 1},
+						Value:   VariableExpr{Name: e},
+					},
+				}},
+				Finally: []Stmt{throw},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			require.Len(t, stmts, 1)
+			assert.Equal(t, tt.expected, stmts[0])
+		})
+	}
+}
+
+func TestTryStatementErrors(t *testing.T) {
+	testCases := []string{
+		"try {}",
+		"try print 1; catch (e) {}",
+		"try {} catch e {}",
+		"try {} catch () {}",
+		"try {} catch (e) print e;",
+		"try {} finally print 1;",
+		"throw;",
+		`throw "boom"`,
+	}
+
+	for _, source := range testCases {
+		t.Run(source, func(t *testing.T) {
+			hadError = false
+			tokens, err := NewScanner(source).scanTokens()
+			require.NoError(t, err)
+			p, err := NewParser(tokens)
+			require.NoError(t, err)
+			_, err = p.Parse()
+			require.NoError(t, err)
+			assert.True(t, hadError)
+			hadError = false
+		})
+	}
+}
+
+func TestSynchronize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected TokenType
+	}{
+		{name: "after_semicolon", source: "1 + ; 2", expected: NUMBER},
+		{name: "var", source: "1 + var", expected: VAR},
+		{name: "return", source: "1 + return", expected: RETURN},
+		{name: "break", source: "1 + break", expected: BREAK},
+		{name: "continue", source: "1 + continue", expected: CONTINUE},
+		{name: "throw", source: "1 + throw", expected: THROW},
+		{name: "end", source: "1 + 2", expected: EOF},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := NewScanner(tt.source).scanTokens()
+			require.NoError(t, err)
+			p, err := NewParser(tokens)
+			require.NoError(t, err)
+			p.Synchronize()
+			assert.Equal(t, tt.expected, p.peek().Type)
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package main
+
+type functionType int
+
+const (
+	functionNone functionType = iota
+	functionFunction
+	functionInitializer
+	functionMethod
+)
+
+type classType int
+
+const (
+	classNone classType = iota
+	classClass
+	classSubclass
+)
+
+// Resolver is a static analysis pass run between parsing and
+// interpreting.  It walks every statement and expression tracking the
+// lexical scopes, and reports scope-correctness errors through
+// TokenError so nothing is executed when the program is malformed.  It
+// also binds every variable reference to the scope declaring it, so a
+// closure keeps seeing the variables it saw when it was declared.
+type Resolver struct {
+	// scopes is a stack of local block scopes, globals are not tracked.
+	// A name maps to false while declared but not yet defined, that is
+	// while its initializer is being resolved.
+	scopes          []map[string]bool
+	currentFunction functionType
+	currentClass    classType
+}
+
+func NewResolver() *Resolver {
+	return &Resolver{}
+}
+
+// Resolve resolves stmts in place.  Expression nodes are values, so each
+// resolved node replaces the original in the tree, carrying where every
+// variable reference it contains was declared.
+func (r *Resolver) Resolve(stmts []Stmt) {
+	for i, stmt := range stmts {
+		stmts[i] = r.resolveStmt(stmt)
+	}
+}
+
+func (r *Resolver) resolveStmt(stmt Stmt) Stmt {
+	switch stmt := stmt.(type) {
+	case BlockStmt:
+		r.beginScope()
+		r.Resolve(stmt.Stmts)
+		r.endScope()
+		return stmt
+	case ClassStmt:
+		r.resolveClass(stmt)
+		return stmt
+	case ExprStmt:
+		stmt.Expr = r.resolveExpr(stmt.Expr)
+		return stmt
+	case FunctionStmt:
+		// define eagerly so the function can refer to itself recursively
+		r.declare(stmt.Name)
+		r.define(stmt.Name)
+		r.resolveFunction(stmt.Params, stmt.Body, functionFunction)
+		return stmt
+	case IfStmt:
+		stmt.Condition = r.resolveExpr(stmt.Condition)
+		stmt.ThenBranch = r.resolveStmt(stmt.ThenBranch)
+		if stmt.ElseBranch != nil {
+			stmt.ElseBranch = r.resolveStmt(stmt.ElseBranch)
+		}
+		return stmt
+	case PrintStmt:
+		stmt.Expr = r.resolveExpr(stmt.Expr)
+		return stmt
+	case ThrowStmt:
+		stmt.Value = r.resolveExpr(stmt.Value)
+		return stmt
+	case TryStmt:
+		r.beginScope()
+		r.Resolve(stmt.Body)
+		r.endScope()
+		if stmt.Catch != nil {
+			r.beginScope()
+			r.declare(stmt.Catch.Name)
+			r.define(stmt.Catch.Name)
+			r.Resolve(stmt.Catch.Body)
+			r.endScope()
+		}
+		if stmt.Finally != nil {
+			r.beginScope()
+			r.Resolve(stmt.Finally)
+			r.endScope()
+		}
+		return stmt
+	case ReturnStmt:
+		if r.currentFunction == functionNone {
+			TokenError(stmt.Keyword, "Can't return from top-level code.")
+		}
+		if stmt.Value != nil {
+			if r.currentFunction == functionInitializer {
+				TokenError(stmt.Keyword, "Can't return a value from an initializer.")
+			}
+			stmt.Value = r.resolveExpr(stmt.Value)
+		}
+		return stmt
+	case VarStmt:
+		r.declare(stmt.Name)
+		if stmt.Expr != nil {
+			stmt.Expr = r.resolveExpr(stmt.Expr)
+		}
+		r.define(stmt.Name)
+		return stmt
+	case WhileStmt:
+		stmt.Condition = r.resolveExpr(stmt.Condition)
+		stmt.Body = r.resolveStmt(stmt.Body)
+		if stmt.Increment != nil {
+			stmt.Increment = r.resolveExpr(stmt.Increment)
+		}
+		return stmt
+	}
+	return stmt
+}
+
+func (r *Resolver) resolveExpr(expr Expr) Expr {
+	switch expr := expr.(type) {
+	case AssignExpr:
+		expr.Value = r.resolveExpr(expr.Value)
+		expr.binding = r.resolveLocal(expr.Name)
+		return expr
+	case BinaryExpr:
+		expr.Left = r.resolveExpr(expr.Left)
+		expr.Right = r.resolveExpr(expr.Right)
+		return expr
+	case CallExpr:
+		expr.Callee = r.resolveExpr(expr.Callee)
+		for i, arg := range expr.Args {
+			expr.Args[i] = r.resolveExpr(arg)
+		}
+		for i, arg := range expr.NamedArgs {
+			expr.NamedArgs[i].Value = r.resolveExpr(arg.Value)
+		}
+		return expr
+	case CompoundAssignExpr:
+		expr.Value = r.resolveExpr(expr.Value)
+		expr.Target = r.resolveExpr(expr.Target)
+		return expr
+	case ConditionalExpr:
+		expr.Condition = r.resolveExpr(expr.Condition)
+		expr.Then = r.resolveExpr(expr.Then)
+		expr.Else = r.resolveExpr(expr.Else)
+		return expr
+	case FunctionExpr:
+		r.resolveFunction(expr.Params, expr.Body, functionFunction)
+		return expr
+	case GetExpr:
+		expr.Object = r.resolveExpr(expr.Object)
+		return expr
+	case GroupingExpr:
+		expr.Expression = r.resolveExpr(expr.Expression)
+		return expr
+	case IncrementExpr:
+		expr.Target = r.resolveExpr(expr.Target)
+		return expr
+	case IndexExpr:
+		expr.Object = r.resolveExpr(expr.Object)
+		expr.Index = r.resolveExpr(expr.Index)
+		return expr
+	case IndexSetExpr:
+		expr.Value = r.resolveExpr(expr.Value)
+		expr.Object = r.resolveExpr(expr.Object)
+		expr.Index = r.resolveExpr(expr.Index)
+		return expr
+	case InterpolationExpr:
+		for i, part := range expr.Parts {
+			expr.Parts[i] = r.resolveExpr(part)
+		}
+		return expr
+	case ListExpr:
+		for i, element := range expr.Elements {
+			expr.Elements[i] = r.resolveExpr(element)
+		}
+		return expr
+	case LiteralExpr:
+		// nothing to resolve
+		return expr
+	case LogicalExpr:
+		expr.Left = r.resolveExpr(expr.Left)
+		expr.Right = r.resolveExpr(expr.Right)
+		return expr
+	case MapExpr:
+		for i := range expr.Keys {
+			expr.Keys[i] = r.resolveExpr(expr.Keys[i])
+			expr.Values[i] = r.resolveExpr(expr.Values[i])
+		}
+		return expr
+	case SetExpr:
+		expr.Value = r.resolveExpr(expr.Value)
+		expr.Object = r.resolveExpr(expr.Object)
+		return expr
+	case SliceExpr:
+		expr.Object = r.resolveExpr(expr.Object)
+		if expr.Start != nil {
+			expr.Start = r.resolveExpr(expr.Start)
+		}
+		if expr.End != nil {
+			expr.End = r.resolveExpr(expr.End)
+		}
+		return expr
+	case SpreadExpr:
+		expr.Expr = r.resolveExpr(expr.Expr)
+		return expr
+	case SuperExpr:
+		if r.currentClass == classNone {
+			TokenError(expr.Keyword, "Can't use 'super' outside of a class.")
+		} else if r.currentClass != classSubclass {
+			TokenError(expr.Keyword, "Can't use 'super' in a class with no superclass.")
+		}
+		expr.binding = r.resolveLocal(expr.Keyword)
+		return expr
+	case ThisExpr:
+		if r.currentClass == classNone {
+			TokenError(expr.Keyword, "Can't use 'this' outside of a class.")
+		}
+		expr.binding = r.resolveLocal(expr.Keyword)
+		return expr
+	case UnaryExpr:
+		expr.Right = r.resolveExpr(expr.Right)
+		return expr
+	case VariableExpr:
+		if len(r.scopes) > 0 {
+			if defined, ok := r.scopes[len(r.scopes)-1][expr.Name.Lexeme]; ok && !defined {
+				TokenError(expr.Name, "Can't read local variable in its own initializer.")
+			}
+		}
+		expr.binding = r.resolveLocal(expr.Name)
+		return expr
+	}
+	return expr
+}
+
+// resolveLocal finds the innermost scope declaring name.  Names not
+// declared in any local scope are assumed to be globals.
+func (r *Resolver) resolveLocal(name Token) binding {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if _, ok := r.scopes[i][name.Lexeme]; ok {
+			return binding{local: true, depth: len(r.scopes) - 1 - i}
+		}
+	}
+	return binding{}
+}
+
+func (r *Resolver) resolveClass(class ClassStmt) {
+	enclosingClass := r.currentClass
+	r.currentClass = classClass
+	defer func() {
+		r.currentClass = enclosingClass
+	}()
+
+	r.declare(class.Name)
+	r.define(class.Name)
+
+	if class.Superclass != nil {
+		if class.Superclass.Name.Lexeme == class.Name.Lexeme {
+			TokenError(class.Superclass.Name, "A class can't inherit from itself.")
+		}
+
+		r.currentClass = classSubclass
+		*class.Superclass = r.resolveExpr(*class.Superclass).(VariableExpr)
+
+		r.beginScope()
+		r.scopes[len(r.scopes)-1]["super"] = true
+		defer r.endScope()
+	}
+
+	r.beginScope()
+	r.scopes[len(r.scopes)-1]["this"] = true
+	for _, method := range class.Methods {
+		kind := functionMethod
+		if method.Name.Lexeme == "init" {
+			kind = functionInitializer
+		}
+		r.resolveFunction(method.Params, method.Body, kind)
+	}
+	r.endScope()
+}
+
+func (r *Resolver) resolveFunction(params []Param, body []Stmt, kind functionType) {
+	enclosingFunction := r.currentFunction
+	r.currentFunction = kind
+	defer func() {
+		r.currentFunction = enclosingFunction
+	}()
+
+	r.beginScope()
+	for i, param := range params {
+		// defaults are evaluated in the function's scope, seeing the
+		// parameters before them
+		if param.Default != nil {
+			params[i].Default = r.resolveExpr(param.Default)
+		}
+		r.declare(param.Name)
+		r.define(param.Name)
+	}
+	r.Resolve(body)
+	r.endScope()
+}
+
+func (r *Resolver) beginScope() {
+	r.scopes = append(r.scopes, map[string]bool{})
+}
+
+func (r *Resolver) endScope() {
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+func (r *Resolver) declare(name Token) {
+	if len(r.scopes) == 0 {
+		return
+	}
+
+	scope := r.scopes[len(r.scopes)-1]
+	if _, ok := scope[name.Lexeme]; ok {
+		TokenError(name, "Already a variable with this name in this scope.")
+	}
+	scope[name.Lexeme] = false
+}
+
+func (r *Resolver) define(name Token) {
+	if len(r.scopes) == 0 {
+		return
+	}
+	r.scopes[len(r.scopes)-1][name.Lexeme] = true
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolverErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		source      string
+		expectError bool
+	}{
+		{
+			name:        "valid_program",
+			source:      `var a = 1; { var b = a; fun f(c) { return b + c; } }`,
+			expectError: false,
+		},
+		{
+			name:        "global_own_initializer",
+			source:      `var a = a;`,
+			expectError: false,
+		},
+		{
+			name:        "local_own_initializer",
+			source:      `var a = 1; { var a = a; }`,
+			expectError: true,
+		},
+		{
+			name:        "shadow_outer_local",
+			source:      `{ var a = 1; { var a = 2; } }`,
+			expectError: false,
+		},
+		{
+			name:        "global_redeclaration",
+			source:      `var a = 1; var a = 2;`,
+			expectError: false,
+		},
+		{
+			name:        "local_redeclaration",
+			source:      `{ var a = 1; var a = 2; }`,
+			expectError: true,
+		},
+		{
+			name:        "duplicate_parameter",
+			source:      `fun f(a, a) {}`,
+			expectError: true,
+		},
+		{
+			name:        "parameter_redeclared_in_body",
+			source:      `fun f(a) { var a = 1; }`,
+			expectError: true,
+		},
+		{
+			name:        "top_level_return",
+			source:      `return 1;`,
+			expectError: true,
+		},
+		{
+			name:        "return_in_block_outside_function",
+			source:      `{ if (true) return; }`,
+			expectError: true,
+		},
+		{
+			name:        "this_in_method",
+			source:      `class A { m() { return this; } }`,
+			expectError: false,
+		},
+		{
+			name:        "this_in_function",
+			source:      `fun f() { return this; }`,
+			expectError: true,
+		},
+		{
+			name:        "return_value_from_initializer",
+			source:      `class A { init() { return 1; } }`,
+			expectError: true,
+		},
+		{
+			name:        "bare_return_from_initializer",
+			source:      `class A { init() { return; } }`,
+			expectError: false,
+		},
+		{
+			name:        "inherit_from_itself",
+			source:      `class A < A {}`,
+			expectError: true,
+		},
+		{
+			name:        "super_in_subclass",
+			source:      `class A {} class B < A { m() { return super.m; } }`,
+			expectError: false,
+		},
+		{
+			name:        "super_without_superclass",
+			source:      `class A { m() { return super.m; } }`,
+			expectError: true,
+		},
+		{
+			name:        "super_outside_class",
+			source:      `fun f() { return super.m; }`,
+			expectError: true,
+		},
+		{
+			name:        "return_in_nested_function",
+			source:      `fun f() { fun g() { return 1; } return g; }`,
+			expectError: false,
+		},
+		{
+			name:        "return_in_lambda",
+			source:      `var f = fun () { return 1; };`,
+			expectError: false,
+		},
+		{
+			name:        "duplicate_lambda_parameter",
+			source:      `var f = fun (a, a) {};`,
+			expectError: true,
+		},
+		{
+			name:        "catch_variable_in_scope",
+			source:      `{ try {} catch (e) { var a = e; } }`,
+			expectError: false,
+		},
+		{
+			name:        "redeclare_catch_variable",
+			source:      `{ try {} catch (e) { var e = 1; } }`,
+			expectError: true,
+		},
+		{
+			name:        "return_in_finally_outside_function",
+			source:      `try {} finally { return; }`,
+			expectError: true,
+		},
+		{
+			name:        "this_in_conditional_branch",
+			source:      `var a = true ? 1 : this;`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+			NewResolver().Resolve(stmts)
+			assert.Equal(t, tt.expectError, hadError)
+			hadError = false
+		})
+	}
+}
+
+func TestResolverErrorMessages(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "own_initializer",
+			source:   "var a = 1;\n{\n  var a = a;\n}",
+			expected: "[line 3] Error at 'a': Can't read local variable in its own initializer.\n",
+		},
+		{
+			name:     "redeclaration",
+			source:   "{\n  var a = 1;\n  var a = 2;\n}",
+			expected: "[line 3] Error at 'a': Already a variable with this name in this scope.\n",
+		},
+		{
+			name:     "top_level_return",
+			source:   "print 1;\nreturn 1;",
+			expected: "[line 2] Error at 'return': Can't return from top-level code.\n",
+		},
+		{
+			name:     "this_outside_class",
+			source:   "fun f() {\n  return this;\n}",
+			expected: "[line 2] Error at 'this': Can't use 'this' outside of a class.\n",
+		},
+		{
+			name:     "return_value_from_initializer",
+			source:   "class A {\n  init() {\n    return 1;\n  }\n}",
+			expected: "[line 3] Error at 'return': Can't return a value from an initializer.\n",
+		},
+		{
+			name:     "inherit_from_itself",
+			source:   "class A < A {}",
+			expected: "[line 1] Error at 'A': A class can't inherit from itself.\n",
+		},
+		{
+			name:     "super_without_superclass",
+			source:   "class A {\n  m() { return super.m; }\n}",
+			expected: "[line 2] Error at 'super': Can't use 'super' in a class with no superclass.\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts := parseSource(t, tt.source)
+
+			var output bytes.Buffer
+			errorOutput = &output
+			defer func() {
+				errorOutput = os.Stderr
+				hadError = false
+			}()
+
+			NewResolver().Resolve(stmts)
+			assert.True(t, hadError)
+			assert.Equal(t, tt.expected, output.String())
+		})
+	}
+}
+
+func TestResolverThisOutsideClass(t *testing.T) {
+	hadError = false
+	NewResolver().Resolve([]Stmt{
+		ExprStmt{
+			Expr: ThisExpr{
+				Keyword: Token{Type: THIS, Lexeme: "this", Line: 1},
+			},
+		},
+	})
+	assert.True(t, hadError)
+	hadError = false
+}
@@ -2,28 +2,37 @@ package main
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 var (
 	keywords = map[string]TokenType{
-		"and":    AND,
-		"class":  CLASS,
-		"else":   ELSE,
-		"false":  FALSE,
-		"for":    FOR,
-		"fun":    FUN,
-		"if":     IF,
-		"nil":    NIL,
-		"or":     OR,
-		"print":  PRINT,
-		"return": RETURN,
-		"super":  SUPER,
-		"this":   THIS,
-		"true":   TRUE,
-		"var":    VAR,
-		"while":  WHILE,
+		"and":      AND,
+		"break":    BREAK,
+		"catch":    CATCH,
+		"class":    CLASS,
+		"continue": CONTINUE,
+		"else":     ELSE,
+		"false":    FALSE,
+		"finally":  FINALLY,
+		"for":      FOR,
+		"fun":      FUN,
+		"if":       IF,
+		"nil":      NIL,
+		"or":       OR,
+		"print":    PRINT,
+		"return":   RETURN,
+		"super":    SUPER,
+		"this":     THIS,
+		"throw":    THROW,
+		"true":     TRUE,
+		"try":      TRY,
+		"var":      VAR,
+		"while":    WHILE,
 	}
 )
 
@@ -31,6 +40,12 @@ type Scanner struct {
 	start, current, line int
 	source               string
 	tokens               []Token
+
+	// interpolations tracks the "${" expressions being scanned inside
+	// string literals, innermost last.  Each entry counts the braces
+	// opened within that expression so the '}' ending it can be told
+	// apart from one closing a nested brace.
+	interpolations []int
 }
 
 func NewScanner(source string) *Scanner {
@@ -92,12 +107,32 @@ func (s *Scanner) addTokenLiteral(tokenType TokenType, literal any) {
 	})
 }
 
+// addStringToken scans the contents of a string literal following the
+// opening '"', or following the '}' closing an embedded expression.  The
+// contents up to the closing '"' produce a STRING token, while contents
+// up to a "${" produce an INTERPOLATION token and leave the scanner to
+// scan the embedded expression as ordinary tokens.
+//
+// Escape sequences are decoded into the token's literal, the lexeme keeps
+// the original source text.
 func (s *Scanner) addStringToken() {
 	startLine := s.line
 
+	var text strings.Builder
 	for s.peek() != '"' && !s.isAtEnd() {
-		if s.advance() == '\n' {
+		if s.peek() == '$' && s.peekNext() == '{' {
+			break
+		}
+
+		c := s.advance()
+		switch c {
+		case '\\':
+			s.escapeSequence(&text)
+		case '\n':
 			s.line++
+			text.WriteByte(byte(c))
+		default:
+			text.WriteByte(byte(c))
 		}
 	}
 
@@ -106,34 +141,279 @@ func (s *Scanner) addStringToken() {
 		return
 	}
 
+	if s.peek() == '$' {
+		// consume the "${"
+		s.advance()
+		s.advance()
+
+		s.addTokenLiteral(INTERPOLATION, text.String())
+		s.interpolations = append(s.interpolations, 0)
+		return
+	}
+
 	// consume the closing "
 	s.advance()
 
+	s.addTokenLiteral(STRING, text.String())
+}
+
+// escapeSequence decodes the escape sequence following a '\\' in a string
+// literal into text, reporting invalid sequences.
+func (s *Scanner) escapeSequence(text *strings.Builder) {
+	if s.isAtEnd() {
+		// reported as an unterminated string
+		return
+	}
+
+	c := s.advance()
+	switch c {
+	case 'n':
+		text.WriteByte('\n')
+	case 't':
+		text.WriteByte('\t')
+	case 'r':
+		text.WriteByte('\r')
+	case '0':
+		text.WriteByte(0)
+	case '\\', '"', '$':
+		text.WriteByte(byte(c))
+	case 'x':
+		// exactly two hex digits naming a code point up to U+00FF
+		digits := ""
+		for len(digits) < 2 && isHexDigit(s.peek()) {
+			digits += string(s.advance())
+		}
+		if len(digits) != 2 {
+			Error(s.line, "Invalid escape sequence: '\\x' must be followed by two hex digits.")
+			return
+		}
+		n, _ := strconv.ParseUint(digits, 16, 8)
+		text.WriteRune(rune(n))
+	case 'u':
+		// one to six hex digits in braces naming any code point
+		if !s.match('{') {
+			Error(s.line, "Invalid escape sequence: '\\u' must be followed by '{'.")
+			return
+		}
+		digits := ""
+		for isHexDigit(s.peek()) {
+			digits += string(s.advance())
+		}
+		if !s.match('}') || len(digits) == 0 || len(digits) > 6 {
+			Error(s.line, "Invalid escape sequence: '\\u{' must be followed by 1 to 6 hex digits and '}'.")
+			return
+		}
+		n, _ := strconv.ParseUint(digits, 16, 32)
+		if !utf8.ValidRune(rune(n)) {
+			Error(s.line, fmt.Sprintf("Invalid escape sequence: '\\u{%s}' is not a valid code point.", digits))
+			return
+		}
+		text.WriteRune(rune(n))
+	case '\n':
+		s.line++
+		Error(s.line-1, "Invalid escape sequence: '\\' at end of line.")
+	default:
+		Error(s.line, fmt.Sprintf("Invalid escape sequence: '\\%c'.", c))
+	}
+}
+
+// addRawStringToken scans a backtick delimited string literal.  Its
+// contents are taken verbatim, without escape sequences or
+// interpolation.
+func (s *Scanner) addRawStringToken() {
+	startLine := s.line
+
+	for s.peek() != '`' && !s.isAtEnd() {
+		if s.advance() == '\n' {
+			s.line++
+		}
+	}
+
+	if s.isAtEnd() {
+		Error(startLine, "Unterminated raw string.")
+		return
+	}
+
+	// consume the closing `
+	s.advance()
+
 	s.addTokenLiteral(STRING, s.source[s.start+1:s.current-1])
 }
 
-func (s *Scanner) addNumberToken() {
-	for unicode.IsDigit(s.peek()) {
-		s.advance()
+// addMultilineStringToken scans a string literal delimited by triple
+// quotes.  The indentation common to every non-blank line is stripped, as
+// are the line break after the opening quotes and the line holding the
+// closing quotes when either has nothing else on it.  Escape sequences are
+// decoded after stripping, and "${" is not treated as interpolation.
+func (s *Scanner) addMultilineStringToken() {
+	startLine := s.line
+
+	// consume the rest of the opening """
+	s.advance()
+	s.advance()
+	bodyStart := s.current
+
+	for !s.isAtEnd() && !strings.HasPrefix(s.source[s.current:], `"""`) {
+		c := s.advance()
+		if c == '\\' && !s.isAtEnd() {
+			// skip the escaped character so \" can't end the string
+			c = s.advance()
+		}
+		if c == '\n' {
+			s.line++
+		}
 	}
 
-	if s.peek() == '.' && unicode.IsDigit(s.peekNext()) {
-		// consume the "."
-		s.advance()
+	if s.isAtEnd() {
+		Error(startLine, "Unterminated multi-line string.")
+		return
+	}
+
+	body := s.source[bodyStart:s.current]
+
+	// consume the closing """
+	s.current += 3
+
+	lines := strings.Split(body, "\n")
+	bodyLine := startLine
+	if len(lines) > 1 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+		bodyLine++
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	s.addTokenLiteral(STRING, decodeEscapes(dedent(lines), bodyLine))
+}
 
-		for unicode.IsDigit(s.peek()) {
+// dedent joins lines after stripping the leading whitespace they all
+// share.  Blank lines are emptied and do not count towards the common
+// indentation.
+func dedent(lines []string) string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	stripped := make([]string, len(lines))
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			stripped[i] = line[indent:]
+		}
+	}
+	return strings.Join(stripped, "\n")
+}
+
+// decodeEscapes decodes the escape sequences in text, reporting invalid
+// ones relative to line, the line text starts on.
+func decodeEscapes(text string, line int) string {
+	sub := &Scanner{
+		source: text,
+		line:   line,
+	}
+
+	var decoded strings.Builder
+	for !sub.isAtEnd() {
+		c := sub.advance()
+		switch c {
+		case '\\':
+			sub.escapeSequence(&decoded)
+		case '\n':
+			sub.line++
+			decoded.WriteByte(byte(c))
+		default:
+			decoded.WriteByte(byte(c))
+		}
+	}
+	return decoded.String()
+}
+
+func isHexDigit(c rune) bool {
+	return unicode.Is(unicode.ASCII_Hex_Digit, c)
+}
+
+// addNumberToken scans a number literal.  Everything that could belong to
+// the literal is consumed before validating it, so a malformed literal
+// such as "0x" or "1_" is reported as a whole instead of being split into
+// several tokens.
+func (s *Scanner) addNumberToken() {
+	prefixed := s.source[s.start] == '0' && strings.ContainsRune("xXbBoO", s.peek())
+
+	for {
+		c := s.peek()
+		if s.isAlphaNumeric(c) {
+			s.advance()
+		} else if c == '.' && unicode.IsDigit(s.peekNext()) {
+			s.advance()
+		} else if (c == '+' || c == '-') && !prefixed && strings.ContainsRune("eE", rune(s.source[s.current-1])) {
 			s.advance()
+		} else {
+			break
 		}
 	}
 
-	n, err := strconv.ParseFloat(string(s.source[s.start:s.current]), 64)
+	text := s.source[s.start:s.current]
+	n, err := parseNumber(text)
 	if err != nil {
-		Error(s.line, fmt.Sprintf("error parsing float: %s", err.Error()))
+		Error(s.line, err.Error())
 		return
 	}
 	s.addTokenLiteral(NUMBER, n)
 }
 
+var (
+	decimalLiteral = regexp.MustCompile(`^[0-9](_?[0-9])*(\.[0-9](_?[0-9])*)?([eE][+-]?[0-9](_?[0-9])*)?$`)
+	hexLiteral     = regexp.MustCompile(`^0[xX][0-9a-fA-F](_?[0-9a-fA-F])*$`)
+	binaryLiteral  = regexp.MustCompile(`^0[bB][01](_?[01])*$`)
+	octalLiteral   = regexp.MustCompile(`^0[oO][0-7](_?[0-7])*$`)
+)
+
+// parseNumber parses the text of a number literal.  Underscores may
+// separate digits but may not start or end a run of digits.  Literals
+// without a fraction or exponent are integers, and one too large for an
+// int64 is an out of range error.
+func parseNumber(text string) (any, error) {
+	digits := strings.ReplaceAll(text, "_", "")
+
+	base := 0
+	switch {
+	case decimalLiteral.MatchString(text):
+		if !strings.ContainsAny(digits, ".eE") {
+			n, err := strconv.ParseInt(digits, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Number literal '%s' is out of range.", text)
+			}
+			return n, nil
+		}
+		n, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Number literal '%s' is out of range.", text)
+		}
+		return n, nil
+	case hexLiteral.MatchString(text):
+		base = 16
+	case binaryLiteral.MatchString(text):
+		base = 2
+	case octalLiteral.MatchString(text):
+		base = 8
+	default:
+		return nil, fmt.Errorf("Malformed number literal '%s'.", text)
+	}
+
+	n, err := strconv.ParseInt(digits[2:], base, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Number literal '%s' is out of range.", text)
+	}
+	return n, nil
+}
+
 func (s *Scanner) isAlpha(c rune) bool {
 	return unicode.IsLetter(c) || c == rune('_')
 }
@@ -155,6 +435,29 @@ func (s *Scanner) addIdentifierToken() {
 	s.addToken(tokenType)
 }
 
+// blockComment skips a /* ... */ comment after its opening "/*".  Block
+// comments nest, so commenting out code that already contains one works.
+func (s *Scanner) blockComment() {
+	startLine := s.line
+
+	for depth := 1; depth > 0; {
+		if s.isAtEnd() {
+			Error(startLine, "Unterminated block comment.")
+			return
+		}
+
+		c := s.advance()
+		switch {
+		case c == '\n':
+			s.line++
+		case c == '/' && s.match('*'):
+			depth++
+		case c == '*' && s.match('/'):
+			depth--
+		}
+	}
+}
+
 // advance (consume and return token) and check for basic
 // single character lexemes.  For multi-character lexemes
 // peek and/or comume more characters to match the lexeme
@@ -167,21 +470,79 @@ func (s *Scanner) scanToken() {
 	case ')':
 		s.addToken(RIGHT_PAREN)
 	case '{':
+		if len(s.interpolations) > 0 {
+			s.interpolations[len(s.interpolations)-1]++
+		}
 		s.addToken(LEFT_BRACE)
 	case '}':
+		if n := len(s.interpolations); n > 0 {
+			if s.interpolations[n-1] == 0 {
+				// end of an embedded expression, resume the string
+				s.interpolations = s.interpolations[:n-1]
+				s.addStringToken()
+				return
+			}
+			s.interpolations[n-1]--
+		}
 		s.addToken(RIGHT_BRACE)
+	case '[':
+		s.addToken(LEFT_BRACKET)
+	case ']':
+		s.addToken(RIGHT_BRACKET)
+	case ':':
+		s.addToken(COLON)
+	case '?':
+		s.addToken(QUESTION)
 	case ',':
 		s.addToken(COMMA)
 	case '.':
-		s.addToken(DOT)
+		if s.peek() == '.' && s.peekNext() == '.' {
+			s.advance()
+			s.advance()
+			s.addToken(ELLIPSIS)
+		} else {
+			s.addToken(DOT)
+		}
 	case '-':
-		s.addToken(MINUS)
+		if s.match('-') {
+			s.addToken(MINUS_MINUS)
+		} else if s.match('=') {
+			s.addToken(MINUS_EQUAL)
+		} else {
+			s.addToken(MINUS)
+		}
 	case '+':
-		s.addToken(PLUS)
+		if s.match('+') {
+			s.addToken(PLUS_PLUS)
+		} else if s.match('=') {
+			s.addToken(PLUS_EQUAL)
+		} else {
+			s.addToken(PLUS)
+		}
 	case ';':
 		s.addToken(SEMICOLON)
 	case '*':
-		s.addToken(STAR)
+		if s.match('*') {
+			s.addToken(STAR_STAR)
+		} else if s.match('=') {
+			s.addToken(STAR_EQUAL)
+		} else {
+			s.addToken(STAR)
+		}
+	case '%':
+		s.addToken(PERCENT)
+	case '&':
+		s.addToken(AMPERSAND)
+	case '|':
+		s.addToken(PIPE)
+	case '^':
+		s.addToken(CARET)
+	case '~':
+		if s.match('/') {
+			s.addToken(TILDE_SLASH)
+		} else {
+			s.addToken(TILDE)
+		}
 	case '!':
 		if s.match('=') {
 			s.addToken(BANG_EQUAL)
@@ -197,12 +558,16 @@ func (s *Scanner) scanToken() {
 	case '<':
 		if s.match('=') {
 			s.addToken(LESS_EQUAL)
+		} else if s.match('<') {
+			s.addToken(LESS_LESS)
 		} else {
 			s.addToken(LESS)
 		}
 	case '>':
 		if s.match('=') {
 			s.addToken(GREATER_EQUAL)
+		} else if s.match('>') {
+			s.addToken(GREATER_GREATER)
 		} else {
 			s.addToken(GREATER)
 		}
@@ -211,6 +576,10 @@ func (s *Scanner) scanToken() {
 			for s.peek() != rune('\n') && !s.isAtEnd() {
 				s.advance()
 			}
+		} else if s.match('*') {
+			s.blockComment()
+		} else if s.match('=') {
+			s.addToken(SLASH_EQUAL)
 		} else {
 			s.addToken(SLASH)
 		}
@@ -221,7 +590,13 @@ func (s *Scanner) scanToken() {
 		s.line++
 	case '"':
 		// string literals
-		s.addStringToken()
+		if s.peek() == '"' && s.peekNext() == '"' {
+			s.addMultilineStringToken()
+		} else {
+			s.addStringToken()
+		}
+	case '`':
+		s.addRawStringToken()
 	default:
 		if unicode.IsDigit(c) {
 			s.addNumberToken()
@@ -239,6 +614,10 @@ func (s *Scanner) scanTokens() ([]Token, error) {
 		s.scanToken()
 	}
 
+	if len(s.interpolations) > 0 {
+		Error(s.line, "Unterminated string interpolation.")
+	}
+
 	s.tokens = append(s.tokens, Token{
 		Type:   EOF,
 		Lexeme: "",
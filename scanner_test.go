@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -264,18 +263,21 @@ func TestAddNumberToken(t *testing.T) {
 		source   string
 		current  int
 		expected string
+		literal  any
 	}{
 		{
 			name:     "int",
 			source:   `var a = 1234;`,
 			current:  8,
 			expected: "1234",
+			literal:  int64(1234),
 		},
 		{
 			name:     "decimal",
 			source:   `var a = 12.34;`,
 			current:  8,
 			expected: "12.34",
+			literal:  12.34,
 		},
 	}
 
@@ -288,16 +290,13 @@ func TestAddNumberToken(t *testing.T) {
 				line:    3,
 			}
 
-			expectedFloat, err := strconv.ParseFloat(tt.expected, 64)
-			require.NoError(t, err)
-
 			assert.Len(t, s.tokens, 0)
 			s.scanToken()
 			require.Len(t, s.tokens, 1)
 			assert.Equal(t, Token{
 				Type:    NUMBER,
 				Lexeme:  tt.expected,
-				Literal: expectedFloat,
+				Literal: tt.literal,
 				Line:    3,
 			}, s.tokens[0])
 			assert.Equal(t, s.current, tt.current+len(tt.expected))
@@ -398,3 +397,526 @@ func TestScanTokens(t *testing.T) {
 		})
 	}
 }
+
+func TestScanInterpolation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected []Token
+	}{
+		{
+			name:   "single",
+			source: `"a ${b} c"`,
+			expected: []Token{
+				{Type: INTERPOLATION, Lexeme: `"a ${`, Literal: "a "},
+				{Type: IDENTIFIER, Lexeme: "b"},
+				{Type: STRING, Lexeme: `} c"`, Literal: " c"},
+				{Type: EOF},
+			},
+		},
+		{
+			name:   "multiple",
+			source: `"${a}${b}"`,
+			expected: []Token{
+				{Type: INTERPOLATION, Lexeme: `"${`, Literal: ""},
+				{Type: IDENTIFIER, Lexeme: "a"},
+				{Type: INTERPOLATION, Lexeme: `}${`, Literal: ""},
+				{Type: IDENTIFIER, Lexeme: "b"},
+				{Type: STRING, Lexeme: `}"`, Literal: ""},
+				{Type: EOF},
+			},
+		},
+		{
+			name:   "nested_string",
+			source: `"${ "}" }"`,
+			expected: []Token{
+				{Type: INTERPOLATION, Lexeme: `"${`, Literal: ""},
+				{Type: STRING, Lexeme: `"}"`, Literal: "}"},
+				{Type: STRING, Lexeme: `}"`, Literal: ""},
+				{Type: EOF},
+			},
+		},
+		{
+			name:   "nested_braces",
+			source: `"${ {1: 2} }"`,
+			expected: []Token{
+				{Type: INTERPOLATION, Lexeme: `"${`, Literal: ""},
+				{Type: LEFT_BRACE, Lexeme: "{"},
+				{Type: NUMBER, Lexeme: "1", Literal: int64(1)},
+				{Type: COLON, Lexeme: ":"},
+				{Type: NUMBER, Lexeme: "2", Literal: int64(2)},
+				{Type: RIGHT_BRACE, Lexeme: "}"},
+				{Type: STRING, Lexeme: `}"`, Literal: ""},
+				{Type: EOF},
+			},
+		},
+		{
+			name:   "nested_interpolation",
+			source: `"a${"b${c}"}"`,
+			expected: []Token{
+				{Type: INTERPOLATION, Lexeme: `"a${`, Literal: "a"},
+				{Type: INTERPOLATION, Lexeme: `"b${`, Literal: "b"},
+				{Type: IDENTIFIER, Lexeme: "c"},
+				{Type: STRING, Lexeme: `}"`, Literal: ""},
+				{Type: STRING, Lexeme: `}"`, Literal: ""},
+				{Type: EOF},
+			},
+		},
+		{
+			name:   "lone_dollar",
+			source: `"$5 {}"`,
+			expected: []Token{
+				{Type: STRING, Lexeme: `"$5 {}"`, Literal: "$5 {}"},
+				{Type: EOF},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			hadError = false
+			s := NewScanner(tt.source)
+			s.line = 0 // to simplify the tests
+			tokens, err := s.scanTokens()
+			require.NoError(t, err)
+			assert.False(t, hadError)
+			assert.Equal(t, tt.expected, tokens)
+		})
+	}
+}
+
+func TestScanUnterminatedInterpolation(t *testing.T) {
+	hadError = false
+	_, err := NewScanner(`"a ${b`).scanTokens()
+	require.NoError(t, err)
+	assert.True(t, hadError)
+	hadError = false
+}
+
+func TestStringEscapes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{
+			name:     "newline",
+			source:   `"a\nb"`,
+			expected: "a\nb",
+		},
+		{
+			name:     "tab_and_carriage_return",
+			source:   `"\t\r"`,
+			expected: "\t\r",
+		},
+		{
+			name:     "backslash",
+			source:   `"a\\b"`,
+			expected: `a\b`,
+		},
+		{
+			name:     "quote",
+			source:   `"say \"hi\""`,
+			expected: `say "hi"`,
+		},
+		{
+			name:     "nul",
+			source:   `"\0"`,
+			expected: "\x00",
+		},
+		{
+			name:     "hex",
+			source:   `"\x41\xe9"`,
+			expected: "Aé",
+		},
+		{
+			name:     "unicode",
+			source:   `"\u{1F600}\u{41}"`,
+			expected: "😀A",
+		},
+		{
+			name:     "dollar",
+			source:   `"\${not interpolated}"`,
+			expected: "${not interpolated}",
+		},
+		{
+			name:     "raw_utf8",
+			source:   `"héllo"`,
+			expected: "héllo",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			hadError = false
+			tokens, err := NewScanner(tt.source).scanTokens()
+			require.NoError(t, err)
+			require.False(t, hadError)
+			require.Len(t, tokens, 2)
+			assert.Equal(t, Token{
+				Type:    STRING,
+				Lexeme:  tt.source,
+				Literal: tt.expected,
+				Line:    1,
+			}, tokens[0])
+		})
+	}
+}
+
+func TestInvalidStringEscapes(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "unknown",
+			source: `"\q"`,
+		},
+		{
+			name:   "short_hex",
+			source: `"\x4"`,
+		},
+		{
+			name:   "unicode_without_brace",
+			source: `"\u0041"`,
+		},
+		{
+			name:   "unicode_empty",
+			source: `"\u{}"`,
+		},
+		{
+			name:   "unicode_too_long",
+			source: `"\u{1234567}"`,
+		},
+		{
+			name:   "unicode_surrogate",
+			source: `"\u{D800}"`,
+		},
+		{
+			name:   "unicode_out_of_range",
+			source: `"\u{110000}"`,
+		},
+		{
+			name:   "unterminated_after_escape",
+			source: `"\"`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			hadError = false
+			_, err := NewScanner(tt.source).scanTokens()
+			require.NoError(t, err)
+			assert.True(t, hadError)
+			hadError = false
+		})
+	}
+}
+
+func TestStringEscapeLineTracking(t *testing.T) {
+	hadError = false
+	tokens, err := NewScanner("\"a\\n\nb\" c").scanTokens()
+	require.NoError(t, err)
+	require.False(t, hadError)
+	require.Len(t, tokens, 3)
+	assert.Equal(t, "a\n\nb", tokens[0].Literal)
+	assert.Equal(t, 2, tokens[1].Line)
+}
+
+func TestRawAndMultilineStrings(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+		line     int
+	}{
+		{
+			name:     "raw",
+			source:   "`C:\\dir\\n ${x} \"q\"`",
+			expected: `C:\dir\n ${x} "q"`,
+			line:     1,
+		},
+		{
+			name:     "raw_multiline",
+			source:   "`a\nb`",
+			expected: "a\nb",
+			line:     2,
+		},
+		{
+			name:     "multiline_single_line",
+			source:   `"""say "hi"!"""`,
+			expected: `say "hi"!`,
+			line:     1,
+		},
+		{
+			name: "multiline_dedent",
+			source: `"""
+    SELECT *
+      FROM t
+
+    WHERE x = 1
+    """`,
+			expected: "SELECT *\n  FROM t\n\nWHERE x = 1",
+			line:     6,
+		},
+		{
+			name: "multiline_escapes_after_dedent",
+			source: `"""
+	a\tb\n
+	\"""
+	"""`,
+			expected: "a\tb\n\n\"\"\"",
+			line:     4,
+		},
+		{
+			name:     "multiline_no_interpolation",
+			source:   `"""${x}"""`,
+			expected: "${x}",
+			line:     1,
+		},
+		{
+			name: "multiline_keeps_content_on_delimiter_lines",
+			source: `"""first
+  second"""`,
+			expected: "first\n  second",
+			line:     2,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			hadError = false
+			tokens, err := NewScanner(tt.source).scanTokens()
+			require.NoError(t, err)
+			require.False(t, hadError)
+			require.Len(t, tokens, 2)
+			assert.Equal(t, Token{
+				Type:    STRING,
+				Lexeme:  tt.source,
+				Literal: tt.expected,
+				Line:    tt.line,
+			}, tokens[0])
+			assert.Equal(t, tt.line, tokens[1].Line)
+		})
+	}
+}
+
+func TestUnterminatedRawAndMultilineStrings(t *testing.T) {
+	for _, source := range []string{"`abc", `"""abc""`, `"""abc\"""`} {
+		t.Run(source, func(t *testing.T) {
+			hadError = false
+			_, err := NewScanner(source).scanTokens()
+			require.NoError(t, err)
+			assert.True(t, hadError)
+			hadError = false
+		})
+	}
+}
+
+func TestBlockComments(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected []Token
+	}{
+		{
+			name:   "inline",
+			source: "a /* comment */ b",
+			expected: []Token{
+				{Type: IDENTIFIER, Lexeme: "a", Line: 1},
+				{Type: IDENTIFIER, Lexeme: "b", Line: 1},
+				{Type: EOF, Line: 1},
+			},
+		},
+		{
+			name:   "multiline",
+			source: "a /* line 1\nline 2\n*/ b",
+			expected: []Token{
+				{Type: IDENTIFIER, Lexeme: "a", Line: 1},
+				{Type: IDENTIFIER, Lexeme: "b", Line: 3},
+				{Type: EOF, Line: 3},
+			},
+		},
+		{
+			name:   "nested",
+			source: "a /* outer /* inner\n */ still comment */ b",
+			expected: []Token{
+				{Type: IDENTIFIER, Lexeme: "a", Line: 1},
+				{Type: IDENTIFIER, Lexeme: "b", Line: 2},
+				{Type: EOF, Line: 2},
+			},
+		},
+		{
+			name:   "contains_line_comment_and_string",
+			source: "/* // \" */ a",
+			expected: []Token{
+				{Type: IDENTIFIER, Lexeme: "a", Line: 1},
+				{Type: EOF, Line: 1},
+			},
+		},
+		{
+			name:   "adjacent_operators",
+			source: "a*/**/b",
+			expected: []Token{
+				{Type: IDENTIFIER, Lexeme: "a", Line: 1},
+				{Type: STAR, Lexeme: "*", Line: 1},
+				{Type: IDENTIFIER, Lexeme: "b", Line: 1},
+				{Type: EOF, Line: 1},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			hadError = false
+			tokens, err := NewScanner(tt.source).scanTokens()
+			require.NoError(t, err)
+			assert.False(t, hadError)
+			assert.Equal(t, tt.expected, tokens)
+		})
+	}
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	for _, source := range []string{"/* abc", "/* /* nested */ abc", "/* abc *"} {
+		t.Run(source, func(t *testing.T) {
+			hadError = false
+			_, err := NewScanner(source).scanTokens()
+			require.NoError(t, err)
+			assert.True(t, hadError)
+			hadError = false
+		})
+	}
+}
+
+func TestScanOperators(t *testing.T) {
+	hadError = false
+	tokens, err := NewScanner("% ** * & | ^ ~ ~/ << <= < >> >= >").scanTokens()
+	require.NoError(t, err)
+	require.False(t, hadError)
+	assert.Equal(t, []TokenType{
+		PERCENT, STAR_STAR, STAR, AMPERSAND, PIPE, CARET, TILDE, TILDE_SLASH,
+		LESS_LESS, LESS_EQUAL, LESS, GREATER_GREATER, GREATER_EQUAL, GREATER, EOF,
+	}, tokenTypes(tokens))
+}
+
+func TestScanAssignmentOperators(t *testing.T) {
+	hadError = false
+	tokens, err := NewScanner("+= -= *= /= ++ -- + - * / **").scanTokens()
+	require.NoError(t, err)
+	require.False(t, hadError)
+	assert.Equal(t, []TokenType{
+		PLUS_EQUAL, MINUS_EQUAL, STAR_EQUAL, SLASH_EQUAL, PLUS_PLUS, MINUS_MINUS,
+		PLUS, MINUS, STAR, SLASH, STAR_STAR, EOF,
+	}, tokenTypes(tokens))
+}
+
+func TestScanEllipsis(t *testing.T) {
+	hadError = false
+	tokens, err := NewScanner("f(...args) a.b .. 1.5").scanTokens()
+	require.NoError(t, err)
+	require.False(t, hadError)
+	assert.Equal(t, []TokenType{
+		IDENTIFIER, LEFT_PAREN, ELLIPSIS, IDENTIFIER, RIGHT_PAREN,
+		IDENTIFIER, DOT, IDENTIFIER, DOT, DOT, NUMBER, EOF,
+	}, tokenTypes(tokens))
+}
+
+func TestNumberLiterals(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{name: "hex", source: "0xFF", expected: int64(255)},
+		{name: "hex_lower", source: "0xdead_beef", expected: int64(0xdeadbeef)},
+		{name: "binary", source: "0b1010", expected: int64(10)},
+		{name: "octal", source: "0o755", expected: int64(0o755)},
+		{name: "negative_exponent", source: "1e-9", expected: 1e-9},
+		{name: "fraction_exponent", source: "6.02E23", expected: 6.02e23},
+		{name: "positive_exponent", source: "2e+3", expected: 2000.},
+		{name: "separators", source: "1_000_000", expected: int64(1000000)},
+		{name: "fraction_separators", source: "3.141_592", expected: 3.141592},
+		{name: "leading_zero", source: "007", expected: int64(7)},
+		{name: "integer_point", source: "2.0", expected: 2.},
+		{name: "max_integer", source: "9223372036854775807", expected: int64(9223372036854775807)},
+		{name: "max_hex_integer", source: "0x7FFF_FFFF_FFFF_FFFF", expected: int64(0x7FFFFFFFFFFFFFFF)},
+		{name: "large_float", source: "9223372036854775808.0", expected: 9223372036854775808.},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			hadError = false
+			tokens, err := NewScanner(tt.source + ";").scanTokens()
+			require.NoError(t, err)
+			require.False(t, hadError)
+			require.Len(t, tokens, 3)
+			assert.Equal(t, Token{
+				Type:    NUMBER,
+				Lexeme:  tt.source,
+				Literal: tt.expected,
+				Line:    1,
+			}, tokens[0])
+		})
+	}
+}
+
+func TestMalformedNumberLiterals(t *testing.T) {
+	testCases := []string{
+		"0x",
+		"0b",
+		"0b102",
+		"0o78",
+		"0xFG",
+		"1_",
+		"1__0",
+		"1_.5",
+		"1e",
+		"1e+",
+		"1e_5",
+		"12abc",
+		"0x_FF",
+		"0xFFFFFFFFFFFFFFFFF",
+		"0xFFFFFFFFFFFFFFFF",
+		"18446744073709551615",
+		"9223372036854775808",
+		"0b1000000000000000000000000000000000000000000000000000000000000000",
+		"1e999",
+	}
+
+	for _, source := range testCases {
+		t.Run(source, func(t *testing.T) {
+			hadError = false
+			tokens, err := NewScanner(source).scanTokens()
+			require.NoError(t, err)
+			assert.True(t, hadError)
+			// the malformed literal is not split into several tokens
+			assert.Equal(t, []Token{{Type: EOF, Line: 1}}, tokens)
+			hadError = false
+		})
+	}
+}
+
+func TestNumberFollowedByOperator(t *testing.T) {
+	hadError = false
+	tokens, err := NewScanner("0xE-1 1.5.x").scanTokens()
+	require.NoError(t, err)
+	require.False(t, hadError)
+	assert.Equal(t, []TokenType{NUMBER, MINUS, NUMBER, NUMBER, DOT, IDENTIFIER, EOF}, tokenTypes(tokens))
+}
+
+func tokenTypes(tokens []Token) []TokenType {
+	types := make([]TokenType, len(tokens))
+	for i, token := range tokens {
+		types[i] = token.Type
+	}
+	return types
+}
+
+func TestScanExceptionKeywords(t *testing.T) {
+	hadError = false
+	tokens, err := NewScanner("try catch finally throw trying").scanTokens()
+	require.NoError(t, err)
+	require.False(t, hadError)
+	assert.Equal(t, []TokenType{TRY, CATCH, FINALLY, THROW, IDENTIFIER, EOF}, tokenTypes(tokens))
+}
@@ -1,11 +1,132 @@
 package main
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 type Stmt interface {
 	Execute() error
 }
 
+type BreakStmt struct {
+	Keyword Token
+	Label   *Token
+}
+
+func (stmt BreakStmt) Execute() error {
+	return BreakSignal{
+		Label: labelName(stmt.Label),
+	}
+}
+
+type ContinueStmt struct {
+	Keyword Token
+	Label   *Token
+}
+
+func (stmt ContinueStmt) Execute() error {
+	return ContinueSignal{
+		Label: labelName(stmt.Label),
+	}
+}
+
+func labelName(label *Token) string {
+	if label == nil {
+		return ""
+	}
+	return label.Lexeme
+}
+
+// BreakSignal unwinds the executing statements up to the loop it
+// targets, the innermost loop when Label is empty.
+type BreakSignal struct {
+	Label string
+}
+
+func (b BreakSignal) Error() string {
+	return "break outside of loop"
+}
+
+// ContinueSignal unwinds the executing statements up to the loop it
+// targets, which then moves on to its next iteration.
+type ContinueSignal struct {
+	Label string
+}
+
+func (c ContinueSignal) Error() string {
+	return "continue outside of loop"
+}
+
+// ThrowSignal unwinds the executing statements up to the nearest
+// enclosing try statement, carrying the thrown value.
+type ThrowSignal struct {
+	Value any
+	Line  int
+}
+
+func (t ThrowSignal) Error() string {
+	return fmt.Sprintf("[line %d] uncaught exception: %s", t.Line, stringify(t.Value))
+}
+
+// isSignal reports whether err is a control-flow signal rather than an
+// error.  Signals pass through try statements without being caught.
+func isSignal(err error) bool {
+	var r ReturnValue
+	var b BreakSignal
+	var c ContinueSignal
+	return errors.As(err, &r) || errors.As(err, &b) || errors.As(err, &c)
+}
+
+type ClassStmt struct {
+	Name       Token
+	Superclass *VariableExpr
+	Methods    []FunctionStmt
+}
+
+func (stmt ClassStmt) Execute() error {
+	var superclass *LoxClass
+	if stmt.Superclass != nil {
+		v, err := stmt.Superclass.Evaluate()
+		if err != nil {
+			return err
+		}
+
+		class, ok := v.(*LoxClass)
+		if !ok {
+			return runtimeError(stmt.Superclass.Name, fmt.Errorf("superclass must be a class: %v", v))
+		}
+		superclass = class
+	}
+
+	// methods of a subclass close over an extra environment defining
+	// 'super' so super expressions can find the superclass
+	enclosing := environment
+	if superclass != nil {
+		environment = NewEnvironment(environment)
+		environment.Define("super", superclass)
+	}
+
+	methods := make(map[string]*LoxFunction, len(stmt.Methods))
+	for _, method := range stmt.Methods {
+		methods[method.Name.Lexeme] = &LoxFunction{
+			name:          method.Name.Lexeme,
+			params:        method.Params,
+			body:          method.Body,
+			closure:       environment,
+			isInitializer: method.Name.Lexeme == "init",
+		}
+	}
+
+	environment = enclosing
+	environment.Define(stmt.Name.Lexeme, &LoxClass{
+		name:       stmt.Name.Lexeme,
+		superclass: superclass,
+		methods:    methods,
+	})
+	return nil
+}
+
 type ExprStmt struct {
 	Expr Expr
 }
@@ -15,6 +136,45 @@ func (stmt ExprStmt) Execute() error {
 	return err
 }
 
+type FunctionStmt struct {
+	Name   Token
+	Params []Param
+	Body   []Stmt
+}
+
+func (stmt FunctionStmt) Execute() error {
+	environment.Define(stmt.Name.Lexeme, &LoxFunction{
+		name:    stmt.Name.Lexeme,
+		params:  stmt.Params,
+		body:    stmt.Body,
+		closure: environment,
+	})
+	return nil
+}
+
+type IfStmt struct {
+	Condition  Expr
+	ThenBranch Stmt
+	ElseBranch Stmt
+}
+
+func (stmt IfStmt) Execute() error {
+	condition, err := stmt.Condition.Evaluate()
+	if err != nil {
+		return err
+	}
+
+	if isTruthy(condition) {
+		return stmt.ThenBranch.Execute()
+	}
+
+	if stmt.ElseBranch != nil {
+		return stmt.ElseBranch.Execute()
+	}
+
+	return nil
+}
+
 type PrintStmt struct {
 	Expr Expr
 }
@@ -24,10 +184,98 @@ func (stmt PrintStmt) Execute() error {
 	if err != nil {
 		return err
 	}
-	fmt.Println(v)
+	fmt.Println(stringify(v))
 	return nil
 }
 
+type ReturnStmt struct {
+	Keyword Token
+	Value   Expr
+}
+
+// Execute unwinds to the enclosing function call by returning the
+// value wrapped in a ReturnValue error.
+func (stmt ReturnStmt) Execute() error {
+	var v any
+	if stmt.Value != nil {
+		vv, err := stmt.Value.Evaluate()
+		if err != nil {
+			return err
+		}
+		v = vv
+	}
+	return ReturnValue{
+		Value: v,
+	}
+}
+
+type ThrowStmt struct {
+	Keyword Token
+	Value   Expr
+}
+
+func (stmt ThrowStmt) Execute() error {
+	v, err := stmt.Value.Evaluate()
+	if err != nil {
+		return err
+	}
+
+	return ThrowSignal{
+		Value: v,
+		Line:  stmt.Keyword.Line,
+	}
+}
+
+type TryStmt struct {
+	Body  []Stmt
+	Catch *CatchClause
+	// Finally is nil without a finally clause.
+	Finally []Stmt
+}
+
+// CatchClause binds the caught value to Name while executing Body.
+type CatchClause struct {
+	Name Token
+	Body []Stmt
+}
+
+// Execute runs the finally clause on every exit from the try and catch
+// clauses, including returns and loop jumps.  A finally clause that exits
+// by itself replaces the exit that was pending.
+func (stmt TryStmt) Execute() error {
+	err := executeBlock(stmt.Body, NewEnvironment(environment))
+
+	if err != nil && stmt.Catch != nil && !isSignal(err) {
+		env := NewEnvironment(environment)
+		env.Define(stmt.Catch.Name.Lexeme, caughtValue(err))
+		err = executeBlock(stmt.Catch.Body, env)
+	}
+
+	if stmt.Finally != nil {
+		if finallyErr := executeBlock(stmt.Finally, NewEnvironment(environment)); finallyErr != nil {
+			return finallyErr
+		}
+	}
+
+	return err
+}
+
+// caughtValue is the value a catch clause receives for err: the value
+// of a throw statement, or an error object for a runtime error.
+func caughtValue(err error) any {
+	var t ThrowSignal
+	if errors.As(err, &t) {
+		return t.Value
+	}
+
+	var r *RuntimeError
+	if errors.As(err, &r) {
+		return &LoxError{message: r.Message, line: r.Line}
+	}
+
+	return &LoxError{message: err.Error()}
+}
+
 type VarStmt struct {
 	Name Token
 	Expr Expr
@@ -46,6 +294,55 @@ func (stmt VarStmt) Execute() error {
 	return nil
 }
 
+// WhileStmt is a loop.  Increment is only set for desugared for loops, it
+// is evaluated after every iteration of the body including those cut
+// short by continue.
+type WhileStmt struct {
+	Condition Expr
+	Body      Stmt
+	Increment Expr
+	Label     string
+}
+
+func (stmt WhileStmt) Execute() error {
+	for {
+		condition, err := stmt.Condition.Evaluate()
+		if err != nil {
+			return err
+		}
+
+		if !isTruthy(condition) {
+			return nil
+		}
+
+		err = stmt.Body.Execute()
+
+		var b BreakSignal
+		if errors.As(err, &b) && stmt.targetedBy(b.Label) {
+			return nil
+		}
+
+		var c ContinueSignal
+		if errors.As(err, &c) && stmt.targetedBy(c.Label) {
+			err = nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if stmt.Increment != nil {
+			if _, err := stmt.Increment.Evaluate(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (stmt WhileStmt) targetedBy(label string) bool {
+	return label == "" || label == stmt.Label
+}
+
 type BlockStmt struct {
 	Stmts []Stmt
 }
@@ -68,3 +365,13 @@ func executeBlock(stmts []Stmt, env *Environment) error {
 	}
 	return nil
 }
+
+// evaluateIn evaluates expr with env as the current environment.
+func evaluateIn(expr Expr, env *Environment) (any, error) {
+	prev := environment
+	environment = env
+	defer func() {
+		environment = prev
+	}()
+	return expr.Evaluate()
+}
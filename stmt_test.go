@@ -0,0 +1,1482 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// execute runs source in a fresh global environment and returns it so
+// tests can inspect the resulting variable bindings.
+func execute(t *testing.T, source string) *Environment {
+	t.Helper()
+	require.NoError(t, executeErr(t, source))
+	return environment
+}
+
+// executeErr runs source in a fresh global environment and returns the
+// first runtime error encountered.
+func executeErr(t *testing.T, source string) error {
+	t.Helper()
+	stmts := parseSource(t, source)
+	NewResolver().Resolve(stmts)
+	require.False(t, hadError)
+
+	globals = newGlobals()
+	environment = globals
+	for _, stmt := range stmts {
+		if err := stmt.Execute(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assertVariable(t *testing.T, env *Environment, name string, expected any) {
+	t.Helper()
+	v, err := env.Get(Token{Lexeme: name})
+	require.NoError(t, err)
+	assert.Equal(t, expected, v)
+}
+
+func TestIfExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "then",
+			source:   `var r; if (true) r = "then"; else r = "else";`,
+			expected: "then",
+		},
+		{
+			name:     "else",
+			source:   `var r; if (false) r = "then"; else r = "else";`,
+			expected: "else",
+		},
+		{
+			name:     "nil_is_falsey",
+			source:   `var r = "unset"; if (nil) r = "then";`,
+			expected: "unset",
+		},
+		{
+			name:     "zero_is_truthy",
+			source:   `var r; if (0) r = "then"; else r = "else";`,
+			expected: "then",
+		},
+		{
+			name:     "dangling_else",
+			source:   `var r = "unset"; if (true) if (false) r = "inner then"; else r = "inner else";`,
+			expected: "inner else",
+		},
+		{
+			name:     "block",
+			source:   `var r; if (true) { var a = "block"; r = a; }`,
+			expected: "block",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestLoopExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "while",
+			source:   `var r = 0; while (r < 5) r = r + 1;`,
+			expected: int64(5),
+		},
+		{
+			name:     "while_false",
+			source:   `var r = "unset"; while (false) r = "body";`,
+			expected: "unset",
+		},
+		{
+			name:     "for",
+			source:   `var r = ""; for (var i = 0; i < 3; i = i + 1) r = r + "x";`,
+			expected: "xxx",
+		},
+		{
+			name:     "for_initializer_scoped",
+			source:   `var r = "outer"; for (var r = 0; r < 3; r = r + 1) {}`,
+			expected: "outer",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestFunctionExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "call",
+			source:   `fun add(a, b) { return a + b; } var r = add(1, 2);`,
+			expected: int64(3),
+		},
+		{
+			name:     "implicit_nil",
+			source:   `fun f() {} var r = f();`,
+			expected: nil,
+		},
+		{
+			name:     "bare_return",
+			source:   `fun f() { return; } var r = f();`,
+			expected: nil,
+		},
+		{
+			name:     "recursion",
+			source:   `fun fib(n) { if (n < 2) return n; return fib(n - 2) + fib(n - 1); } var r = fib(10);`,
+			expected: int64(55),
+		},
+		{
+			name:     "return_unwinds_blocks",
+			source:   `fun f() { var i = 0; while (true) { { i = i + 1; if (i == 3) return i; } } } var r = f();`,
+			expected: int64(3),
+		},
+		{
+			name:     "environment_restored",
+			source:   `var r = "global"; fun f() { { var r = "local"; return r; } } f();`,
+			expected: "global",
+		},
+		{
+			name:     "parameters_shadow",
+			source:   `var a = "global"; fun f(a) { return a; } var r = f("param");`,
+			expected: "param",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestCallErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{
+			name:   "not_callable",
+			source: `"not a function"();`,
+			errMsg: "can only call functions and classes",
+		},
+		{
+			name:   "too_few_arguments",
+			source: `fun f(a, b) {} f(1);`,
+			errMsg: "missing argument for parameter 'b'",
+		},
+		{
+			name:   "too_many_arguments",
+			source: `fun f(a, b) {} f(1, 2, 3);`,
+			errMsg: "expected 2 arguments but got 3",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeErr(t, tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestClosures(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name: "counter",
+			source: `
+				fun makeCounter() {
+					var i = 0;
+					fun count() {
+						i = i + 1;
+						return i;
+					}
+					return count;
+				}
+				var counter = makeCounter();
+				counter();
+				counter();
+				var r = counter();`,
+			expected: int64(3),
+		},
+		{
+			name: "independent_counters",
+			source: `
+				fun makeCounter() {
+					var i = 0;
+					fun count() {
+						i = i + 1;
+						return i;
+					}
+					return count;
+				}
+				var a = makeCounter();
+				var b = makeCounter();
+				a();
+				a();
+				var r = b();`,
+			expected: int64(1),
+		},
+		{
+			name: "outlives_block",
+			source: `
+				var f;
+				{
+					var captured = "block";
+					fun g() {
+						return captured;
+					}
+					f = g;
+				}
+				var r = f();`,
+			expected: "block",
+		},
+		{
+			name: "lexical_not_dynamic",
+			source: `
+				var x = "global";
+				fun get() {
+					return x;
+				}
+				fun caller() {
+					var x = "caller";
+					return get();
+				}
+				var r = caller();`,
+			expected: "global",
+		},
+		{
+			name: "later_declaration_in_block",
+			source: `
+				var a = "global";
+				var r = "";
+				{
+					fun showA() {
+						r = r + a;
+					}
+					showA();
+					var a = "block";
+					showA();
+				}`,
+			expected: "globalglobal",
+		},
+		{
+			name: "assign_after_shadowing",
+			source: `
+				var a = "global";
+				{
+					fun setA() {
+						a = "assigned";
+					}
+					var a = "block";
+					setA();
+				}
+				var r = a;`,
+			expected: "assigned",
+		},
+		{
+			name: "factory",
+			source: `
+				fun adder(n) {
+					fun add(m) {
+						return n + m;
+					}
+					return add;
+				}
+				var r = adder(2)(3);`,
+			expected: int64(5),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestClassExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "fields",
+			source:   `class A {} var a = A(); a.x = 1; a.y = 2; var r = a.x + a.y;`,
+			expected: int64(3),
+		},
+		{
+			name:     "method",
+			source:   `class A { greet(name) { return "hi " + name; } } var r = A().greet("bob");`,
+			expected: "hi bob",
+		},
+		{
+			name:     "initializer",
+			source:   `class P { init(x, y) { this.x = x; this.y = y; } sum() { return this.x + this.y; } } var r = P(1, 2).sum();`,
+			expected: int64(3),
+		},
+		{
+			name:     "initializer_early_return",
+			source:   `class A { init() { this.x = "set"; return; this.x = "unreachable"; } } var r = A().x;`,
+			expected: "set",
+		},
+		{
+			name:     "bound_method_remembers_this",
+			source:   `class A { init(v) { this.v = v; } get() { return this.v; } } var m = A("bound").get; var r = m();`,
+			expected: "bound",
+		},
+		{
+			name:     "field_shadows_method",
+			source:   `class A { m() { return "method"; } } var a = A(); a.m = "field"; var r = a.m;`,
+			expected: "field",
+		},
+		{
+			name:     "this_in_closure",
+			source:   `class A { init() { this.v = "closure"; } f() { fun g() { return this.v; } return g; } } var r = A().f()();`,
+			expected: "closure",
+		},
+		{
+			name:     "calling_init_returns_this",
+			source:   `class A { init() { this.n = 1; } } var a = A(); a.n = 2; var r = a.init().n;`,
+			expected: int64(1),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestClassErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{
+			name:   "undefined_property",
+			source: `class A {} A().missing;`,
+			errMsg: "undefined property 'missing'",
+		},
+		{
+			name:   "get_on_non_instance",
+			source: `var s = "str"; s.length;`,
+			errMsg: "only instances have properties",
+		},
+		{
+			name:   "set_on_non_instance",
+			source: `var n = 1; n.x = 2;`,
+			errMsg: "only instances have fields",
+		},
+		{
+			name:   "initializer_arity",
+			source: `class A { init(a) {} } A();`,
+			errMsg: "missing argument for parameter 'a'",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeErr(t, tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestInheritanceExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "inherited_method",
+			source:   `class A { m() { return "A"; } } class B < A {} var r = B().m();`,
+			expected: "A",
+		},
+		{
+			name:     "inherited_through_chain",
+			source:   `class A { m() { return "A"; } } class B < A {} class C < B {} var r = C().m();`,
+			expected: "A",
+		},
+		{
+			name:     "override",
+			source:   `class A { m() { return "A"; } } class B < A { m() { return "B"; } } var r = B().m();`,
+			expected: "B",
+		},
+		{
+			name:     "super_call",
+			source:   `class A { m() { return "A"; } } class B < A { m() { return "B" + super.m(); } } var r = B().m();`,
+			expected: "BA",
+		},
+		{
+			name:     "super_binds_this",
+			source:   `class A { name() { return this.n; } } class B < A { init() { this.n = "instance"; } name() { return super.name(); } } var r = B().name();`,
+			expected: "instance",
+		},
+		{
+			name:     "super_is_lexical",
+			source:   `class A { m() { return "A"; } } class B < A { m() { return "B"; } test() { return super.m(); } } class C < B {} var r = C().test();`,
+			expected: "A",
+		},
+		{
+			name:     "inherited_initializer",
+			source:   `class A { init(v) { this.v = v; } } class B < A {} var r = B("init").v;`,
+			expected: "init",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestInheritanceErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{
+			name:   "non_class_superclass",
+			source: `var A = "not a class"; class B < A {}`,
+			errMsg: "superclass must be a class",
+		},
+		{
+			name:   "undefined_super_method",
+			source: `class A {} class B < A { m() { return super.missing(); } } B().m();`,
+			errMsg: "undefined property 'missing'",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeErr(t, tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoopJumpExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "break",
+			source:   `var r = 0; while (true) { r = r + 1; if (r == 3) break; }`,
+			expected: int64(3),
+		},
+		{
+			name:     "break_infinite_for",
+			source:   `var r = 0; for (;;) { r = r + 1; if (r == 4) break; }`,
+			expected: int64(4),
+		},
+		{
+			name:     "continue_runs_increment",
+			source:   `var r = ""; for (var i = 0; i < 5; i = i + 1) { if (i == 2) continue; r = r + str(i); }`,
+			expected: "0134",
+		},
+		{
+			name:     "continue_while",
+			source:   `var r = 0; var i = 0; while (i < 5) { i = i + 1; if (i == 3) continue; r = r + i; }`,
+			expected: int64(12),
+		},
+		{
+			name:     "break_innermost",
+			source:   `var r = 0; for (var i = 0; i < 3; i = i + 1) { while (true) { break; } r = r + 1; }`,
+			expected: int64(3),
+		},
+		{
+			name:     "labeled_break",
+			source:   `var r = ""; outer: for (var i = 0; i < 3; i = i + 1) { for (var j = 0; j < 3; j = j + 1) { if (j == 1) break outer; r = r + str(i) + str(j); } }`,
+			expected: "00",
+		},
+		{
+			name:     "labeled_continue",
+			source:   `var r = ""; outer: for (var i = 0; i < 3; i = i + 1) { for (var j = 0; j < 3; j = j + 1) { if (j == 1) continue outer; r = r + str(i) + str(j); } }`,
+			expected: "001020",
+		},
+		{
+			name:     "environment_restored",
+			source:   `var r = "global"; while (true) { var r = "loop"; { var r = "block"; break; } }`,
+			expected: "global",
+		},
+		{
+			name:     "return_from_loop",
+			source:   `fun f() { while (true) { return "returned"; } } var r = f();`,
+			expected: "returned",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestListExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "index",
+			source:   `var r = [1, 2, 3][1];`,
+			expected: int64(2),
+		},
+		{
+			name:     "negative_index",
+			source:   `var r = ["a", "b", "c"][-1];`,
+			expected: "c",
+		},
+		{
+			name:     "index_assignment",
+			source:   `var a = [1, 2, 3]; a[0] = "x"; var r = a[0];`,
+			expected: "x",
+		},
+		{
+			name:     "shared_by_reference",
+			source:   `var a = [1]; var b = a; b[0] = 2; var r = a[0];`,
+			expected: int64(2),
+		},
+		{
+			name:     "slice_copies",
+			source:   `var a = [1, 2, 3]; var b = a[0:2]; b[0] = 9; var r = str(a) + str(b);`,
+			expected: "[1, 2, 3][9, 2]",
+		},
+		{
+			name:     "nested",
+			source:   `var a = [[1, 2], [3, 4]]; a[1][0] = 5; var r = a[1][0];`,
+			expected: int64(5),
+		},
+		{
+			name:     "len",
+			source:   `var r = len([1, 2, 3][1:]);`,
+			expected: int64(2),
+		},
+		{
+			name:     "loop",
+			source:   `var a = [1, 2, 3]; var r = 0; for (var i = 0; i < len(a); i = i + 1) r = r + a[i];`,
+			expected: int64(6),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestListErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{
+			name:   "out_of_range",
+			source: `[1, 2][2];`,
+			errMsg: "list index 2 out of range for list of length 2",
+		},
+		{
+			name:   "assign_out_of_range",
+			source: `var a = []; a[0] = 1;`,
+			errMsg: "list index 0 out of range for list of length 0",
+		},
+		{
+			name:   "index_non_list",
+			source: `var a = 1; a[0];`,
+			errMsg: "only lists and maps can be indexed",
+		},
+		{
+			name:   "slice_non_list",
+			source: `var a = "abc"; a[0:1];`,
+			errMsg: "only lists can be sliced",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeErr(t, tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestMapExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "index",
+			source:   `var r = {"a": 1, "b": 2}["b"];`,
+			expected: int64(2),
+		},
+		{
+			name:     "key_types",
+			source:   `var m = {"s": "string", 1: "number", true: "bool", nil: "nil"}; var r = m["s"] + m[1] + m[true] + m[nil];`,
+			expected: "stringnumberboolnil",
+		},
+		{
+			name:     "index_assignment",
+			source:   `var m = {}; m["k"] = "v"; var r = m["k"];`,
+			expected: "v",
+		},
+		{
+			name:     "has",
+			source:   `var m = {"a": nil}; var r = str(has(m, "a")) + str(has(m, "b"));`,
+			expected: "truefalse",
+		},
+		{
+			name:     "delete",
+			source:   `var m = {"a": 1, "b": 2}; delete(m, "a"); var r = str(m) + str(len(m));`,
+			expected: "{b: 2}1",
+		},
+		{
+			name:     "iteration_order",
+			source:   `var m = {"z": 1, "a": 2}; m["m"] = 3; var k = keys(m); var r = ""; for (var i = 0; i < len(k); i = i + 1) r = r + k[i] + str(m[k[i]]);`,
+			expected: "z1a2m3",
+		},
+		{
+			name:     "values",
+			source:   `var r = str(values({"a": 1, "b": [2]}));`,
+			expected: "[1, [2]]",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestMapErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{
+			name:   "missing_key",
+			source: `var m = {}; m["missing"];`,
+			errMsg: "undefined map key: missing",
+		},
+		{
+			name:   "unhashable_key",
+			source: `var m = {[1]: 1};`,
+			errMsg: "map key should be a string, number, boolean or nil",
+		},
+		{
+			name:   "has_non_map",
+			source: `has([], 1);`,
+			errMsg: "has() argument should be a map",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeErr(t, tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestInterpolationExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "expressions",
+			source:   `var name = "Bob"; var age = 41; var r = "Hello ${name}, you are ${age + 1}";`,
+			expected: "Hello Bob, you are 42",
+		},
+		{
+			name:     "stringified_like_print",
+			source:   `var r = "${nil} ${true} ${2.5} ${[1, "a"]}";`,
+			expected: "nil true 2.5 [1, a]",
+		},
+		{
+			name:     "nested_quotes",
+			source:   `var m = {"k": "v"}; var r = "value: ${m["k"]}";`,
+			expected: "value: v",
+		},
+		{
+			name:     "nested_interpolation",
+			source:   `var a = "x"; var r = "<${"(${a})"}>";`,
+			expected: "<(x)>",
+		},
+		{
+			name:     "call",
+			source:   `fun twice(s) { return s + s; } var r = "${twice("ab")}";`,
+			expected: "abab",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestNumberExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "large_id",
+			source:   `var r = 9007199254740992 + 1;`,
+			expected: int64(9007199254740993),
+		},
+		{
+			name:     "float_script_unchanged",
+			source:   `var r = 7 / 2 + 0.5;`,
+			expected: 4.,
+		},
+		{
+			name:     "integer_division",
+			source:   `var r = 7 ~/ 2 + 7 % 2;`,
+			expected: int64(4),
+		},
+		{
+			name:     "promotion",
+			source:   `var r = 2 * 1.5;`,
+			expected: 3.,
+		},
+		{
+			name:     "float_list_index",
+			source:   `var r = [1, 2, 3][2.0];`,
+			expected: int64(3),
+		},
+		{
+			name:     "operators",
+			source:   `var r = 2 ** 3 ** 2 % 100 + (0xF0 | 0x0F) - (1 << 8) + (~0 & 7);`,
+			expected: int64(18),
+		},
+		{
+			name:     "float_map_key",
+			source:   `var m = {1: "one"}; var r = m[1.0];`,
+			expected: "one",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestIntegerOverflowExecute(t *testing.T) {
+	err := executeErr(t, `var r = 9223372036854775807 + 1;`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "integer overflow in binary '+' expression: 9223372036854775807, 1")
+}
+
+func TestConditionalExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "then",
+			source:   `var r = 1 < 2 ? "yes" : "no";`,
+			expected: "yes",
+		},
+		{
+			name:     "else",
+			source:   `var r = nil ? "yes" : "no";`,
+			expected: "no",
+		},
+		{
+			name:     "chained",
+			source:   `var n = 0; var r = n < 0 ? "negative" : n == 0 ? "zero" : "positive";`,
+			expected: "zero",
+		},
+		{
+			name:     "only_chosen_branch_evaluated",
+			source:   `var r = 0; var x = true ? (r = 1) : (r = 2);`,
+			expected: int64(1),
+		},
+		{
+			name:     "untaken_branch_errors_ignored",
+			source:   `var r = false ? -"oops" : "fine";`,
+			expected: "fine",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestCompoundAssignmentExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "plus_equal",
+			source:   `var r = 1; r += 2;`,
+			expected: int64(3),
+		},
+		{
+			name:     "string_plus_equal",
+			source:   `var r = "a"; r += "b";`,
+			expected: "ab",
+		},
+		{
+			name:     "value_of_assignment",
+			source:   `var a = 10; var r = (a -= 4) * 2;`,
+			expected: int64(12),
+		},
+		{
+			name:     "slash_equal_promotes",
+			source:   `var r = 7; r /= 2;`,
+			expected: 3.5,
+		},
+		{
+			name:     "field",
+			source:   `class C {} var c = C(); c.n = 2; c.n *= 5; var r = c.n;`,
+			expected: int64(10),
+		},
+		{
+			name:     "map_entry",
+			source:   `var m = {"k": 1}; m["k"] += 1; var r = m["k"];`,
+			expected: int64(2),
+		},
+		{
+			name:     "target_evaluated_once",
+			source:   `var calls = 0; fun i() { calls += 1; return 0; } var l = [5]; l[i()] += 1; l[i()]++; var r = [calls, l[0]];`,
+			expected: NewLoxList([]any{int64(2), int64(7)}),
+		},
+		{
+			name:     "prefix_returns_new_value",
+			source:   `var a = 1; var r = ++a;`,
+			expected: int64(2),
+		},
+		{
+			name:     "postfix_returns_old_value",
+			source:   `var a = 1; var r = [a--, a];`,
+			expected: NewLoxList([]any{int64(1), int64(0)}),
+		},
+		{
+			name:     "closure_counter",
+			source:   `fun counter() { var n = 0; fun next() { return ++n; } return next; } var c = counter(); c(); var r = c();`,
+			expected: int64(2),
+		},
+		{
+			name:     "for_increment",
+			source:   `var r = 0; for (var i = 0; i < 5; i++) r += i;`,
+			expected: int64(10),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestCompoundAssignmentErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{
+			name:   "type_error",
+			source: `var a = "s"; a -= 1;`,
+			errMsg: "left operand of binary '-' expression should be number: s",
+		},
+		{
+			name:   "mixed_plus",
+			source: `var a = "s"; a++;`,
+			errMsg: "left and right operant of '+' expression should both be numbers or both be strings: s, 1",
+		},
+		{
+			name:   "undefined_variable",
+			source: `a += 1;`,
+			errMsg: "undefined variable",
+		},
+		{
+			name:   "undefined_property",
+			source: `class C {} var c = C(); c.n += 1;`,
+			errMsg: "undefined property 'n'",
+		},
+		{
+			name:   "index_out_of_range",
+			source: `var l = [1]; l[3]++;`,
+			errMsg: "list index 3 out of range for list of length 1",
+		},
+		{
+			name:   "overflow",
+			source: `var a = 9223372036854775807; a++;`,
+			errMsg: "integer overflow in binary '+' expression",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeErr(t, tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLambdaExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "call",
+			source:   `var add = fun (a, b) { return a + b; }; var r = add(1, 2);`,
+			expected: int64(3),
+		},
+		{
+			name:     "callback",
+			source:   `fun apply(f, x) { return f(x); } var r = apply(fun (x) { return x * x; }, 4);`,
+			expected: int64(16),
+		},
+		{
+			name:     "closure",
+			source:   `fun adder(n) { return fun (x) { return x + n; }; } var r = adder(10)(5);`,
+			expected: int64(15),
+		},
+		{
+			name:     "captures_block_variable",
+			source:   `var fs = [nil, nil, nil]; for (var i = 0; i < 3; i++) { var j = i * 10; fs[i] = fun () { return j; }; } var r = fs[0]() + fs[2]();`,
+			expected: int64(20),
+		},
+		{
+			name:     "immediately_invoked",
+			source:   `var r = fun () { return "iife"; }();`,
+			expected: "iife",
+		},
+		{
+			name:     "stringify",
+			source:   `var r = str(fun () {});`,
+			expected: "<fn>",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestDefaultsAndNamedArgumentsExecute(t *testing.T) {
+	connect := `fun connect(host, port = 5432, timeout = 30) { return "${host}:${port}/${timeout}"; } `
+
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "all_defaults",
+			source:   connect + `var r = connect("db");`,
+			expected: "db:5432/30",
+		},
+		{
+			name:     "positional_override",
+			source:   connect + `var r = connect("db", 1);`,
+			expected: "db:1/30",
+		},
+		{
+			name:     "named_skips_default",
+			source:   connect + `var r = connect("db", timeout: 5);`,
+			expected: "db:5432/5",
+		},
+		{
+			name:     "all_named",
+			source:   connect + `var r = connect(timeout: 1, host: "h", port: 2);`,
+			expected: "h:2/1",
+		},
+		{
+			name:     "default_uses_earlier_parameter",
+			source:   `fun f(a, b = a * 2) { return b; } var r = f(4);`,
+			expected: int64(8),
+		},
+		{
+			name:     "default_evaluated_per_call",
+			source:   `fun f(m = {}) { m[len(m)] = true; return len(m); } var r = [f(), f()];`,
+			expected: NewLoxList([]any{int64(1), int64(1)}),
+		},
+		{
+			name:     "default_sees_closure",
+			source:   `var base = 10; fun f(a = base) { return a; } var r = f();`,
+			expected: int64(10),
+		},
+		{
+			name:     "initializer",
+			source:   `class P { init(x = 0, y = 0) { this.x = x; this.y = y; } } var p = P(y: 3); var r = [p.x, p.y];`,
+			expected: NewLoxList([]any{int64(0), int64(3)}),
+		},
+		{
+			name:     "method",
+			source:   `class C { m(a, b = "b") { return a + b; } } var r = C().m(b: "!", a: "a");`,
+			expected: "a!",
+		},
+		{
+			name:     "lambda",
+			source:   `var f = fun (a = 1) { return a; }; var r = f() + f(a: 2);`,
+			expected: int64(3),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestArity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "required",
+			source:   `fun f(a, b) {}`,
+			expected: 2,
+		},
+		{
+			name:     "defaults_not_counted",
+			source:   `fun f(a, b = 1, c = 2) {}`,
+			expected: 1,
+		},
+		{
+			name:     "rest_not_counted",
+			source:   `fun f(a, ...rest) {}`,
+			expected: 1,
+		},
+		{
+			name:     "lambda",
+			source:   `var f = fun (a = 1, ...rest) {};`,
+			expected: 0,
+		},
+		{
+			name:     "class_initializer",
+			source:   `class A { init(a, b = 1) {} } class f < A {}`,
+			expected: 1,
+		},
+		{
+			name:     "class_without_initializer",
+			source:   `class f {}`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			v, err := env.Get(Token{Lexeme: "f"})
+			require.NoError(t, err)
+			callable, ok := v.(Callable)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, callable.Arity())
+		})
+	}
+}
+
+func TestDefaultsAndNamedArgumentsErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{
+			name:   "missing_required",
+			source: `fun connect(host, port = 5432) {} connect(port: 1);`,
+			errMsg: "missing argument for parameter 'host'",
+		},
+		{
+			name:   "unknown_parameter",
+			source: `fun connect(host, port = 5432) {} connect("db", retries: 3);`,
+			errMsg: "unknown parameter 'retries' for <fn connect>",
+		},
+		{
+			name:   "passed_twice",
+			source: `fun connect(host, port = 5432) {} connect("db", host: "other");`,
+			errMsg: "parameter 'host' was passed both positionally and by name",
+		},
+		{
+			name:   "too_many_with_defaults",
+			source: `fun f(a, b = 1) {} f(1, 2, 3);`,
+			errMsg: "expected at most 2 arguments but got 3",
+		},
+		{
+			name:   "class_without_initializer",
+			source: `class C {} C(a: 1);`,
+			errMsg: "unknown parameter 'a' for C",
+		},
+		{
+			name:   "native",
+			source: `len(s: "abc");`,
+			errMsg: "<native fn len> does not take named arguments",
+		},
+		{
+			name:   "default_error",
+			source: `fun f(a = -"s") {} f();`,
+			errMsg: "operand for unary '-' expression should be a number",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeErr(t, tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestRestAndSpreadExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "rest_collects_extra",
+			source:   `fun log(fmt, ...args) { return args; } var r = log("f", 1, 2);`,
+			expected: NewLoxList([]any{int64(1), int64(2)}),
+		},
+		{
+			name:     "rest_empty",
+			source:   `fun log(fmt, ...args) { return args; } var r = log("f");`,
+			expected: NewLoxList([]any{}),
+		},
+		{
+			name:     "rest_after_default",
+			source:   `fun f(a, b = 2, ...rest) { return [a, b, len(rest)]; } var r = f(1);`,
+			expected: NewLoxList([]any{int64(1), int64(2), int64(0)}),
+		},
+		{
+			name:     "rest_with_named",
+			source:   `fun f(a, b = 2, ...rest) { return [a, b, rest]; } var r = f(a: 1, b: 3);`,
+			expected: NewLoxList([]any{int64(1), int64(3), NewLoxList([]any{})}),
+		},
+		{
+			name:     "spread",
+			source:   `fun add(a, b, c) { return a + b + c; } var l = [1, 2]; var r = add(...l, 3);`,
+			expected: int64(6),
+		},
+		{
+			name:     "spread_into_rest",
+			source:   `fun count(...xs) { return len(xs); } var r = count(0, ...[1, 2], ...[], 3);`,
+			expected: int64(4),
+		},
+		{
+			name:     "forward_rest",
+			source:   `fun inner(a, b) { return a - b; } fun outer(...args) { return inner(...args); } var r = outer(5, 3);`,
+			expected: int64(2),
+		},
+		{
+			name:     "spread_into_native",
+			source:   `var r = len(...["abc"]);`,
+			expected: int64(3),
+		},
+		{
+			name:     "rest_is_a_copy",
+			source:   `var l = [1, 2]; fun f(...xs) { xs[0] = 9; } f(...l); var r = l[0];`,
+			expected: int64(1),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestRestAndSpreadErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{
+			name:   "spread_too_many",
+			source: `fun f(a, b) {} f(...[1, 2, 3]);`,
+			errMsg: "expected 2 arguments but got 3",
+		},
+		{
+			name:   "spread_too_few",
+			source: `fun f(a, b) {} f(...[1]);`,
+			errMsg: "missing argument for parameter 'b'",
+		},
+		{
+			name:   "spread_non_list",
+			source: `fun f(a) {} f(..."abc");`,
+			errMsg: "spread argument should be a list: abc",
+		},
+		{
+			name:   "rest_by_name",
+			source: `fun f(...args) {} f(args: [1]);`,
+			errMsg: "rest parameter 'args' can't be passed by name",
+		},
+		{
+			name:   "missing_before_rest",
+			source: `fun f(a, ...args) {} f();`,
+			errMsg: "missing argument for parameter 'a'",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeErr(t, tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestExceptionExecute(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected any
+	}{
+		{
+			name:     "catch_thrown_value",
+			source:   `var r; try { throw "boom"; } catch (e) { r = e; }`,
+			expected: "boom",
+		},
+		{
+			name:     "catch_thrown_map",
+			source:   `var r; try { throw {"code": 42}; } catch (e) { r = e["code"]; }`,
+			expected: int64(42),
+		},
+		{
+			name:     "throw_from_function",
+			source:   `fun f() { throw "inner"; } var r; try { f(); } catch (e) { r = e; }`,
+			expected: "inner",
+		},
+		{
+			name:     "catch_type_error_message",
+			source:   `var r; try { 1 - "a"; } catch (e) { r = e.message; }`,
+			expected: "right operand of binary '-' expression should be number: a",
+		},
+		{
+			name: "catch_type_error_line",
+			source: `var r;
+try {
+  -"a";
+} catch (e) { r = e.line; }`,
+			expected: int64(3),
+		},
+		{
+			name: "catch_invalid_map_key_line",
+			source: `var r;
+try {
+  var m = {
+    "a": 1,
+    [1]: 2,
+    "b": 3
+  };
+} catch (e) { r = e.line; }`,
+			expected: int64(5),
+		},
+		{
+			name:     "catch_undefined_variable",
+			source:   `var r; try { missing; } catch (e) { r = e.message; }`,
+			expected: "undefined variable 'missing'",
+		},
+		{
+			name:     "no_error_skips_catch",
+			source:   `var r = "body"; try { r = r + "!"; } catch (e) { r = "catch"; }`,
+			expected: "body!",
+		},
+		{
+			name:     "finally_on_normal_exit",
+			source:   `var r = ""; try { r = r + "t"; } catch (e) { r = r + "c"; } finally { r = r + "f"; }`,
+			expected: "tf",
+		},
+		{
+			name:     "finally_after_catch",
+			source:   `var r = ""; try { throw 1; } catch (e) { r = r + "c"; } finally { r = r + "f"; }`,
+			expected: "cf",
+		},
+		{
+			name:     "finally_on_return",
+			source:   `var r = ""; fun f() { try { return 1; } finally { r = "f"; } } var v = f(); r = r + str(v);`,
+			expected: "f1",
+		},
+		{
+			name:     "finally_on_break",
+			source:   `var r = 0; while (true) { try { break; } finally { r = r + 1; } }`,
+			expected: int64(1),
+		},
+		{
+			name:     "finally_on_continue",
+			source:   `var r = 0; for (var i = 0; i < 3; i++) { try { continue; } finally { r = r + 1; } }`,
+			expected: int64(3),
+		},
+		{
+			name:     "finally_return_overrides",
+			source:   `fun f() { try { return 1; } finally { return 2; } } var r = f();`,
+			expected: int64(2),
+		},
+		{
+			name:     "finally_return_discards_throw",
+			source:   `fun f() { try { throw "lost"; } finally { return "kept"; } } var r = f();`,
+			expected: "kept",
+		},
+		{
+			name:     "rethrow",
+			source:   `var r; try { try { throw "a"; } catch (e) { throw e + "b"; } } catch (e) { r = e; }`,
+			expected: "ab",
+		},
+		{
+			name:     "finally_runs_when_catch_throws",
+			source:   `var r = ""; try { try { throw 1; } catch (e) { throw 2; } finally { r = "f"; } } catch (e) { r = r + str(e); }`,
+			expected: "f2",
+		},
+		{
+			name:     "catch_ignores_break",
+			source:   `var r = "loop"; while (true) { try { break; } catch (e) { r = "caught"; } }`,
+			expected: "loop",
+		},
+		{
+			name:     "catch_ignores_return",
+			source:   `fun f() { try { return "returned"; } catch (e) { return "caught"; } } var r = f();`,
+			expected: "returned",
+		},
+		{
+			name:     "catch_variable_is_scoped",
+			source:   `var e = "outer"; try { throw "inner"; } catch (e) {} var r = e;`,
+			expected: "outer",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			env := execute(t, tt.source)
+			assertVariable(t, env, "r", tt.expected)
+		})
+	}
+}
+
+func TestExceptionErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{
+			name:   "uncaught_throw",
+			source: `throw "boom";`,
+			errMsg: "[line 1] uncaught exception: boom",
+		},
+		{
+			name:   "finally_does_not_catch",
+			source: `try { throw "boom"; } finally {}`,
+			errMsg: "uncaught exception: boom",
+		},
+		{
+			name: "runtime_error_line",
+			source: `var a = 1;
+a + nil;`,
+			errMsg: "[line 2] left and right operant of '+' expression",
+		},
+		{
+			name: "invalid_map_key_line",
+			source: `var m = {
+  [1]: 2,
+  "a": 3
+};`,
+			errMsg: "[line 2] map key should be",
+		},
+		{
+			name:   "error_object_missing_property",
+			source: `try { nil(); } catch (e) { e.code; }`,
+			errMsg: "undefined property 'code'",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executeErr(t, tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
@@ -6,50 +6,82 @@ type TokenType string
 
 const (
 	// Single-character tokens.
-	LEFT_PAREN  TokenType = "LEFT_PAREN"
-	RIGHT_PAREN TokenType = "RIGHT_PAREN"
-	LEFT_BRACE  TokenType = "LEFT_BRACE"
-	RIGHT_BRACE TokenType = "RIGHT_BRACE"
-	COMMA       TokenType = "COMMA"
-	DOT         TokenType = "DOT"
-	MINUS       TokenType = "MINUS"
-	PLUS        TokenType = "PLUS"
-	SEMICOLON   TokenType = "SEMICOLON"
-	SLASH       TokenType = "SLASH"
-	STAR        TokenType = "STAR"
+	LEFT_PAREN    TokenType = "LEFT_PAREN"
+	RIGHT_PAREN   TokenType = "RIGHT_PAREN"
+	LEFT_BRACE    TokenType = "LEFT_BRACE"
+	RIGHT_BRACE   TokenType = "RIGHT_BRACE"
+	LEFT_BRACKET  TokenType = "LEFT_BRACKET"
+	RIGHT_BRACKET TokenType = "RIGHT_BRACKET"
+	COLON         TokenType = "COLON"
+	COMMA         TokenType = "COMMA"
+	DOT           TokenType = "DOT"
+	MINUS         TokenType = "MINUS"
+	PLUS          TokenType = "PLUS"
+	SEMICOLON     TokenType = "SEMICOLON"
+	SLASH         TokenType = "SLASH"
+	PERCENT       TokenType = "PERCENT"
+	QUESTION      TokenType = "QUESTION"
+	AMPERSAND     TokenType = "AMPERSAND"
+	PIPE          TokenType = "PIPE"
+	CARET         TokenType = "CARET"
+	TILDE         TokenType = "TILDE"
 
 	// One or two character tokens.
-	BANG          TokenType = "BANG"
-	BANG_EQUAL    TokenType = "BANG_EQUAL"
-	EQUAL         TokenType = "EQUAL"
-	EQUAL_EQUAL   TokenType = "EQUAL_EQUAL"
-	GREATER       TokenType = "GREATER"
-	GREATER_EQUAL TokenType = "GREATER_EQUAL"
-	LESS          TokenType = "LESS"
-	LESS_EQUAL    TokenType = "LESS_EQUAL"
+	BANG            TokenType = "BANG"
+	BANG_EQUAL      TokenType = "BANG_EQUAL"
+	EQUAL           TokenType = "EQUAL"
+	EQUAL_EQUAL     TokenType = "EQUAL_EQUAL"
+	GREATER         TokenType = "GREATER"
+	GREATER_EQUAL   TokenType = "GREATER_EQUAL"
+	GREATER_GREATER TokenType = "GREATER_GREATER"
+	LESS            TokenType = "LESS"
+	LESS_EQUAL      TokenType = "LESS_EQUAL"
+	LESS_LESS       TokenType = "LESS_LESS"
+	ELLIPSIS        TokenType = "ELLIPSIS"
+	MINUS_EQUAL     TokenType = "MINUS_EQUAL"
+	MINUS_MINUS     TokenType = "MINUS_MINUS"
+	PLUS_EQUAL      TokenType = "PLUS_EQUAL"
+	PLUS_PLUS       TokenType = "PLUS_PLUS"
+	SLASH_EQUAL     TokenType = "SLASH_EQUAL"
+	STAR            TokenType = "STAR"
+	STAR_EQUAL      TokenType = "STAR_EQUAL"
+	STAR_STAR       TokenType = "STAR_STAR"
+	TILDE_SLASH     TokenType = "TILDE_SLASH"
 
 	// Literals.
 	IDENTIFIER TokenType = "IDENTIFIER"
 	STRING     TokenType = "STRING"
 	NUMBER     TokenType = "NUMBER"
 
+	// INTERPOLATION is the part of a string literal up to an embedded
+	// "${" expression.  The tokens of the expression follow it, and the
+	// rest of the string continues as a further INTERPOLATION or a
+	// closing STRING token.
+	INTERPOLATION TokenType = "INTERPOLATION"
+
 	// Keywords.
-	AND    TokenType = "AND"
-	CLASS  TokenType = "CLASS"
-	ELSE   TokenType = "ELSE"
-	FALSE  TokenType = "FALSE"
-	FUN    TokenType = "FUN"
-	FOR    TokenType = "FOR"
-	IF     TokenType = "IF"
-	NIL    TokenType = "NIL"
-	OR     TokenType = "OR"
-	PRINT  TokenType = "PRINT"
-	RETURN TokenType = "RETURN"
-	SUPER  TokenType = "SUPER"
-	THIS   TokenType = "THIS"
-	TRUE   TokenType = "TRUE"
-	VAR    TokenType = "VAR"
-	WHILE  TokenType = "WHILE"
+	AND      TokenType = "AND"
+	BREAK    TokenType = "BREAK"
+	CATCH    TokenType = "CATCH"
+	CLASS    TokenType = "CLASS"
+	CONTINUE TokenType = "CONTINUE"
+	ELSE     TokenType = "ELSE"
+	FALSE    TokenType = "FALSE"
+	FINALLY  TokenType = "FINALLY"
+	FUN      TokenType = "FUN"
+	FOR      TokenType = "FOR"
+	IF       TokenType = "IF"
+	NIL      TokenType = "NIL"
+	OR       TokenType = "OR"
+	PRINT    TokenType = "PRINT"
+	RETURN   TokenType = "RETURN"
+	SUPER    TokenType = "SUPER"
+	THIS     TokenType = "THIS"
+	THROW    TokenType = "THROW"
+	TRUE     TokenType = "TRUE"
+	TRY      TokenType = "TRY"
+	VAR      TokenType = "VAR"
+	WHILE    TokenType = "WHILE"
 
 	EOF TokenType = "EOF"
 )